@@ -0,0 +1,44 @@
+package output
+
+import "net/http"
+
+// JobPayload is the Data sent by Accepted. This provides a consistent format for
+// handing off long-running operations to be processed asynchronously.
+type JobPayload struct {
+	//ID identifies the job.
+	ID string
+
+	//StatusURL is where the client can check the status of the job.
+	StatusURL string `json:",omitempty"`
+
+	//RetryAfter is the number of seconds the client should wait before checking
+	//the status of the job. This is set using WithRetryAfter.
+	RetryAfter int `json:",omitempty"`
+}
+
+// Accepted is used when a request was accepted for processing but the processing
+// has not completed, i.e. a job will run asynchronously. This returns an HTTP status
+// 202, sets the Location header to statusURL (if provided), and Data holds a
+// JobPayload. Use WithRetryAfter to tell the client when to check the status.
+//
+//	output.Accepted(jobID, "/jobs/"+jobID, w, output.WithRetryAfter(5*time.Second))
+func Accepted(jobID string, statusURL string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.Accepted(jobID, statusURL, w, opts...)
+	return
+}
+
+// Accepted sends a 202 for an asynchronous job. See the package-level Accepted.
+func (r *Responder) Accepted(jobID string, statusURL string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	if statusURL != "" {
+		opts = append([]SendOption{WithHeader("Location", statusURL)}, opts...)
+	}
+
+	jp := JobPayload{
+		ID:         jobID,
+		StatusURL:  statusURL,
+		RetryAfter: applySendOptions(opts).retryAfter,
+	}
+
+	err = r.buildAndSend(true, TypeAccepted, jp, ErrorPayload{}, w, http.StatusAccepted, opts...)
+	return
+}
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// SendBlob is used to send a large binary, or already encoded, document as the
+// response body, without the Payload wrapped around it. Range requests are supported
+// so that clients, such as download managers, can resume interrupted transfers: a
+// request with a Range header is sent an HTTP status 206 with only the requested
+// part of content and a Content-Range header.
+//
+// Ranges are only supported when the ResponseWriter carries the request, see
+// Negotiate. Otherwise, the whole of content is sent. Use WithETag so that clients
+// can use the If-Range header to make sure content has not changed between
+// requests.
+//
+//	f, err := os.Open(path)
+//	...
+//	output.SendBlob(f, "application/pdf", output.Negotiate(w, r), output.WithETag(version))
+func SendBlob(content io.ReadSeeker, contentType string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.SendBlob(content, contentType, w, opts...)
+	return
+}
+
+// SendBlob sends content as the response body. See the package-level SendBlob.
+func (r *Responder) SendBlob(content io.ReadSeeker, contentType string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	opts = append(opts, WithRaw())
+	err = r.DataFoundReader(content, contentType, w, opts...)
+	return
+}
+
+// serveRange sends the content read from s, honoring the request's Range header,
+// using http.ServeContent. False is returned if this cannot be done because s is not
+// seekable, the ResponseWriter does not carry the request, or the response is not
+// a successful response.
+func serveRange(w http.ResponseWriter, s *dataStream, responseCode int) bool {
+	rs, ok := s.r.(io.ReadSeeker)
+	if !ok || responseCode != http.StatusOK {
+		return false
+	}
+
+	req := requestFrom(w)
+	if req == nil {
+		return false
+	}
+
+	//ServeContent sets the status code, Content-Length, Content-Range, and handles
+	//conditional requests using the ETag header, if set.
+	http.ServeContent(w, req, "", time.Time{}, rs)
+	return true
+}
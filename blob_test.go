@@ -0,0 +1,112 @@
+package output
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendBlob(t *testing.T) {
+	const content = "0123456789"
+
+	tests := []struct {
+		name             string
+		method           string
+		header           map[string]string
+		opts             []SendOption
+		withoutRequest   bool
+		wantCode         int
+		wantBody         string
+		wantContentRange string
+	}{
+		{
+			name:     "whole",
+			method:   http.MethodGet,
+			wantCode: http.StatusOK,
+			wantBody: content,
+		},
+		{
+			name:             "range",
+			method:           http.MethodGet,
+			header:           map[string]string{"Range": "bytes=2-5"},
+			wantCode:         http.StatusPartialContent,
+			wantBody:         "2345",
+			wantContentRange: "bytes 2-5/10",
+		},
+		{
+			name:             "suffix range",
+			method:           http.MethodGet,
+			header:           map[string]string{"Range": "bytes=-3"},
+			wantCode:         http.StatusPartialContent,
+			wantBody:         "789",
+			wantContentRange: "bytes 7-9/10",
+		},
+		{
+			name:             "unsatisfiable range",
+			method:           http.MethodGet,
+			header:           map[string]string{"Range": "bytes=20-30"},
+			wantCode:         http.StatusRequestedRangeNotSatisfiable,
+			wantContentRange: "bytes */10",
+		},
+		{
+			name:             "if-range matching",
+			method:           http.MethodGet,
+			header:           map[string]string{"Range": "bytes=0-1", "If-Range": `"v1"`},
+			opts:             []SendOption{WithETag("v1")},
+			wantCode:         http.StatusPartialContent,
+			wantBody:         "01",
+			wantContentRange: "bytes 0-1/10",
+		},
+		{
+			name:     "if-range not matching",
+			method:   http.MethodGet,
+			header:   map[string]string{"Range": "bytes=0-1", "If-Range": `"v0"`},
+			opts:     []SendOption{WithETag("v1")},
+			wantCode: http.StatusOK,
+			wantBody: content,
+		},
+		{
+			name:     "head",
+			method:   http.MethodHead,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:           "range without request",
+			method:         http.MethodGet,
+			header:         map[string]string{"Range": "bytes=2-5"},
+			withoutRequest: true,
+			wantCode:       http.StatusOK,
+			wantBody:       content,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+
+			rec := httptest.NewRecorder()
+			var w http.ResponseWriter = rec
+			if !tt.withoutRequest {
+				w = Negotiate(rec, req)
+			}
+
+			if err := New().SendBlob(strings.NewReader(content), "text/plain", w, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d", rec.Code, tt.wantCode)
+			}
+			if got := rec.Header().Get("Content-Range"); got != tt.wantContentRange {
+				t.Fatalf("got Content-Range %q, want %q", got, tt.wantContentRange)
+			}
+			if tt.wantCode != http.StatusRequestedRangeNotSatisfiable && rec.Body.String() != tt.wantBody {
+				t.Fatalf("got body %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
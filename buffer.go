@@ -0,0 +1,102 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// bufWriterSize is the size of the buffer used when encoding directly to a
+// ResponseWriter. This matches the buffer size net/http uses for responses.
+const bufWriterSize = 4 << 10
+
+// bufWriters are reused when encoding responses directly to a ResponseWriter.
+var bufWriters = sync.Pool{
+	New: func() interface{} {
+		return bufio.NewWriterSize(nil, bufWriterSize)
+	},
+}
+
+// getBufWriter returns a buffered writer, from the pool, that writes to w.
+func getBufWriter(w io.Writer) *bufio.Writer {
+	bw := bufWriters.Get().(*bufio.Writer)
+	bw.Reset(w)
+	return bw
+}
+
+// putBufWriter returns bw to the pool.
+func putBufWriter(bw *bufio.Writer) {
+	//Don't hold on to the ResponseWriter.
+	bw.Reset(nil)
+	bufWriters.Put(bw)
+}
+
+// maxPooledBufferSize is the capacity above which a buffer is not returned to the
+// pool. This prevents a few large responses from keeping large buffers in memory.
+const maxPooledBufferSize = 64 << 10
+
+// buffers are reused when encoding responses into a buffer.
+var buffers = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	b := buffers.Get().(*bytes.Buffer)
+	b.Reset()
+	return b
+}
+
+// putBuffer returns b to the pool. b, and any slice of its contents, must not be
+// used afterwards.
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	buffers.Put(b)
+}
+
+// payloads are reused for Payloads built by the Responder's funcs.
+var payloads = sync.Pool{
+	New: func() interface{} {
+		return new(Payload)
+	},
+}
+
+// getPayload returns a zero Payload from the pool.
+func getPayload() *Payload {
+	return payloads.Get().(*Payload)
+}
+
+// putPayload zeros p, so that Data is not kept in memory, and returns it to the
+// pool.
+func putPayload(p *Payload) {
+	*p = Payload{}
+	payloads.Put(p)
+}
+
+// buffered reports if the response being sent to w must be encoded into a buffer,
+// versus directly to w, because the encoded response is altered, or its size or
+// signature is needed, before it is sent.
+func (r *Responder) buffered(w http.ResponseWriter, contentType string, streaming bool) bool {
+	if streaming || r.contentLength || r.compressionMinSize > 0 || r.maxResponseSize > 0 || len(r.signingKey) > 0 {
+		return true
+	}
+
+	//The body of a response to a HEAD request is encoded, but not sent, so that
+	//the Content-Length header can be set.
+	if isHead(w) {
+		return true
+	}
+
+	if !isJSON(contentType) {
+		return false
+	}
+
+	return r.canonicalJSON || !r.escapeHTML || r.jwsSigner != nil || r.pretty(w)
+}
@@ -0,0 +1,92 @@
+package output
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// CachePolicy describes how a response can be cached, sent as the Cache-Control
+// header.
+type CachePolicy struct {
+	//MaxAge is how long the response can be cached for. If this is zero, caches
+	//must revalidate the response before using it.
+	MaxAge time.Duration
+
+	//Private prevents shared caches, such as proxies and CDNs, from caching the
+	//response. Use this for responses specific to a user.
+	Private bool
+
+	//NoStore prevents the response from being cached at all. MaxAge and Private
+	//are ignored.
+	NoStore bool
+}
+
+// noStore is the CachePolicy used for error responses by default.
+var noStore = CachePolicy{NoStore: true}
+
+// String returns the value of the Cache-Control header for the policy.
+func (cp CachePolicy) String() string {
+	if cp.NoStore {
+		return "no-store"
+	}
+
+	scope := "public"
+	if cp.Private {
+		scope = "private"
+	}
+
+	if cp.MaxAge <= 0 {
+		return scope + ", no-cache"
+	}
+
+	return scope + ", max-age=" + strconv.FormatInt(int64(cp.MaxAge/time.Second), 10)
+}
+
+// WithCache sets the Cache-Control header of the response. This overrides the
+// CachePolicy set for the message type with WithCachePolicy.
+//
+//	output.DataFound(countries, w, output.WithCache(time.Hour, false, false))
+func WithCache(maxAge time.Duration, private, noStore bool) SendOption {
+	return func(o *sendOptions) {
+		o.cache = &CachePolicy{
+			MaxAge:  maxAge,
+			Private: private,
+			NoStore: noStore,
+		}
+	}
+}
+
+// WithCachePolicy sets the Cache-Control header of every response with the message
+// type msgType so that caching is consistent across endpoints and services.
+//
+// Error responses are sent with "no-store" unless a CachePolicy is set for their
+// message type, or provided with WithCache, since errors are usually temporary.
+// Other responses do not have a Cache-Control header unless a CachePolicy is set.
+func WithCachePolicy(msgType MessageType, cp CachePolicy) Option {
+	return func(r *Responder) {
+		if r.cachePolicies == nil {
+			r.cachePolicies = make(map[MessageType]CachePolicy)
+		}
+
+		r.cachePolicies[msgType] = cp
+	}
+}
+
+// setCacheControl sets the Cache-Control header of the response based on the
+// CachePolicy provided with WithCache, set for the Payload's message type, or the
+// default for errors.
+func (r *Responder) setCacheControl(w http.ResponseWriter, p *Payload, o sendOptions) {
+	var cp *CachePolicy
+	if o.cache != nil {
+		cp = o.cache
+	} else if typePolicy, ok := r.cachePolicies[MessageType(p.Type)]; ok {
+		cp = &typePolicy
+	} else if !p.OK {
+		cp = &noStore
+	}
+
+	if cp != nil {
+		w.Header().Set("Cache-Control", cp.String())
+	}
+}
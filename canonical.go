@@ -0,0 +1,96 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// WithCanonicalJSON causes JSON responses to be canonicalized so that the same
+// Payload is always encoded to the same bytes. Object keys are sorted, insignificant
+// whitespace is removed, and numbers are formatted the same way regardless of how
+// they were provided. This is useful for signing responses, caching by hash, and
+// golden-file tests.
+//
+// Note that a canonicalized response is not pretty-printed, see WithPrettyJSON.
+func WithCanonicalJSON(b bool) Option {
+	return func(r *Responder) {
+		r.canonicalJSON = b
+	}
+}
+
+// canonicalJSON returns j canonicalized, see WithCanonicalJSON. j is returned as is
+// if it cannot be canonicalized. A trailing newline, as written by json.Encoder, is
+// kept so that canonicalized responses end the same way as other responses.
+func canonicalJSON(j []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.UseNumber()
+
+	g, err := decodeGeneric(dec)
+	if err != nil {
+		return j
+	}
+
+	c, err := json.Marshal(canonicalize(g))
+	if err != nil {
+		return j
+	}
+
+	if bytes.HasSuffix(j, []byte("\n")) {
+		c = append(c, '\n')
+	}
+
+	return c
+}
+
+// canonicalize sorts the members of objects in g by key and formats numbers
+// consistently.
+func canonicalize(g interface{}) interface{} {
+	switch v := g.(type) {
+	case genericMap:
+		m := make(genericMap, len(v))
+		for i, member := range v {
+			m[i] = genericMember{Key: member.Key, Value: canonicalize(member.Value)}
+		}
+		sort.SliceStable(m, func(i, j int) bool {
+			return m[i].Key < m[j].Key
+		})
+		return m
+
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, value := range v {
+			a[i] = canonicalize(value)
+		}
+		return a
+
+	case json.Number:
+		return canonicalNumber(v)
+
+	default:
+		return v
+	}
+}
+
+// canonicalNumber formats n consistently. Integers are kept as is, so that large
+// integers do not lose precision, while other numbers are formatted as encoding/json
+// formats a float64.
+// For example, 1.50 and 1.5e0 are both formatted as 1.5.
+func canonicalNumber(n json.Number) json.Number {
+	if !strings.ContainsAny(n.String(), ".eE") {
+		return n
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return n
+	}
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		return n
+	}
+
+	return json.Number(b)
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "sorted keys",
+			in:   `{"b":1,"a":2,"c":{"z":1,"y":2}}`,
+			want: `{"a":2,"b":1,"c":{"y":2,"z":1}}`,
+		},
+		{
+			name: "whitespace",
+			in:   "{ \"a\" : [ 1 , 2 ] }",
+			want: `{"a":[1,2]}`,
+		},
+		{
+			name: "trailing newline",
+			in:   "{\"b\":1,\"a\":2}\n",
+			want: "{\"a\":2,\"b\":1}\n",
+		},
+		{
+			name: "numbers",
+			in:   `[1.50,1.5e0,100,1E2,12345678901234567890]`,
+			want: `[1.5,1.5,100,100,12345678901234567890]`,
+		},
+		{
+			name: "arrays keep order",
+			in:   `[3,1,2]`,
+			want: `[3,1,2]`,
+		},
+		{
+			name: "invalid",
+			in:   `{"a":`,
+			want: `{"a":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(canonicalJSON([]byte(tt.in))); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalJSONResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		canonical bool
+	}{
+		{"default", false},
+		{"canonical", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(WithCanonicalJSON(tt.canonical))
+
+			w := httptest.NewRecorder()
+			if err := r.DataFound(map[string]int{"b": 1, "a": 2}, Negotiate(w, httptest.NewRequest(http.MethodGet, "/", nil))); err != nil {
+				t.Fatal(err)
+			}
+
+			//Responses end with a newline whether or not they are canonicalized.
+			body := w.Body.Bytes()
+			if len(body) == 0 || body[len(body)-1] != '\n' {
+				t.Fatalf("got %q, want a trailing newline", body)
+			}
+		})
+	}
+}
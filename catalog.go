@@ -0,0 +1,185 @@
+package output
+
+import (
+	"errors"
+	"net/url"
+)
+
+// ErrorInfo describes an error in the error catalog. The catalog is used to
+// populate an ErrorPayload automatically based on the error being sent.
+type ErrorInfo struct {
+	//Code is set as the ErrorPayload's Code.
+	Code string
+
+	//Message is used as the ErrorPayload's Message when no message is provided
+	//when sending the error.
+	Message string
+
+	//DocsURL is set as the ErrorPayload's DocsURL. If this is blank and a docs base
+	//URL was set (see SetDocsBaseURL), the DocsURL is built from the base URL and
+	//the Code.
+	DocsURL string
+
+	//Retryable is set as the ErrorPayload's Retryable. This can be overridden for
+	//a single response with WithRetryable.
+	Retryable bool
+}
+
+// errorCatalogEntry maps an error to its ErrorInfo.
+type errorCatalogEntry struct {
+	target error
+	info   ErrorInfo
+}
+
+// predefinedErrors are the catalog entries for errors defined in this package.
+// These are checked, on every Responder, after the errors registered with
+// RegisterError.
+var predefinedErrors = []errorCatalogEntry{
+	{errInputInvalid, ErrorInfo{Code: "inputInvalid"}},
+	{errAlreadyExists, ErrorInfo{Code: "alreadyExists"}},
+	{errBadRequest, ErrorInfo{Code: "badRequest"}},
+	{errUnauthorized, ErrorInfo{Code: "unauthorized"}},
+	{errForbidden, ErrorInfo{Code: "forbidden"}},
+	{errNotFound, ErrorInfo{Code: "notFound"}},
+	{errConflict, ErrorInfo{Code: "conflict"}},
+	{errUnprocessable, ErrorInfo{Code: "unprocessable"}},
+	{errMethodNotAllowed, ErrorInfo{Code: "methodNotAllowed"}},
+	{errServiceUnavailable, ErrorInfo{Code: "serviceUnavailable", Retryable: true}},
+	{errMaintenance, ErrorInfo{Code: "maintenance", Retryable: true}},
+	{errTimeout, ErrorInfo{Code: "timeout", Retryable: true}},
+	{errValidation, ErrorInfo{Code: "validation"}},
+	{errMultiple, ErrorInfo{Code: "multiple"}},
+	{errPanic, ErrorInfo{Code: "panic"}},
+	{errResponseTooLarge, ErrorInfo{Code: "responseTooLarge"}},
+	{errSchemaMismatch, ErrorInfo{Code: "schemaMismatch"}},
+}
+
+// PredefinedErrorCodes returns the Codes of the errors defined in this package, in
+// the order they are registered. This allows clients, such as outputclient, to
+// match every Code this package can send.
+func PredefinedErrorCodes() (codes []string) {
+	codes = make([]string, 0, len(predefinedErrors))
+	for _, e := range predefinedErrors {
+		codes = append(codes, e.info.Code)
+	}
+
+	return
+}
+
+// RegisterError adds an error to the default Responder's error catalog. See
+// Responder.RegisterError.
+//
+//	output.RegisterError(ErrCardDeclined, output.ErrorInfo{
+//		Code:    "cardDeclined",
+//		Message: "The card was declined, please use a different card.",
+//		DocsURL: "https://developer.example.com/errors/cardDeclined",
+//	})
+func RegisterError(target error, info ErrorInfo) {
+	std.RegisterError(target, info)
+}
+
+// RegisterErrorCode registers the Code set in the ErrorPayload when an error
+// matching target is sent by the default Responder. This is shorthand for
+// RegisterError with only a Code.
+//
+//	output.RegisterErrorCode(ErrCardDeclined, "cardDeclined")
+func RegisterErrorCode(target error, code string) {
+	std.RegisterErrorCode(target, code)
+}
+
+// SetDocsBaseURL sets the base URL used to build an ErrorPayload's DocsURL for the
+// default Responder. See Responder.SetDocsBaseURL.
+func SetDocsBaseURL(base string) {
+	std.SetDocsBaseURL(base)
+}
+
+// WithRegisteredError adds an error to the error catalog when creating a
+// Responder. See Responder.RegisterError.
+func WithRegisteredError(target error, info ErrorInfo) Option {
+	return func(r *Responder) {
+		r.RegisterError(target, info)
+	}
+}
+
+// WithRegisteredErrorCode registers an error code when creating a Responder. See
+// Responder.RegisterErrorCode.
+func WithRegisteredErrorCode(target error, code string) Option {
+	return func(r *Responder) {
+		r.RegisterErrorCode(target, code)
+	}
+}
+
+// WithDocsBaseURL sets the base URL used to build an ErrorPayload's DocsURL. See
+// Responder.SetDocsBaseURL.
+func WithDocsBaseURL(base string) Option {
+	return func(r *Responder) {
+		r.SetDocsBaseURL(base)
+	}
+}
+
+// RegisterError adds an error to the error catalog. When an error matching target
+// is sent with Error, or any related func, the ErrorPayload's Code, DocsURL, and
+// Retryable are set from info, and info's Message is used if no message was
+// provided. Matching is done with errors.Is so wrapped errors are matched as well.
+//
+// Entries are checked in the order they were registered, the first match is used,
+// the same as with MapError. Errors you register take precedence over the errors
+// defined in this package.
+func (r *Responder) RegisterError(target error, info ErrorInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errorCatalog = append(r.errorCatalog, errorCatalogEntry{
+		target: target,
+		info:   info,
+	})
+}
+
+// RegisterErrorCode registers the Code set in the ErrorPayload when an error
+// matching target is sent. See RegisterError.
+func (r *Responder) RegisterErrorCode(target error, code string) {
+	r.RegisterError(target, ErrorInfo{Code: code})
+}
+
+// SetDocsBaseURL sets the base URL used to build an ErrorPayload's DocsURL when
+// the error catalog entry for an error has a Code but no DocsURL. The Code is
+// appended to base as a path segment, for example a base of
+// "https://developer.example.com/errors/" results in a DocsURL of
+// "https://developer.example.com/errors/notFound".
+func (r *Responder) SetDocsBaseURL(base string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.docsBaseURL = base
+}
+
+// errorInfoFor returns the catalog entry for err. A zero ErrorInfo is returned if
+// err is not in the catalog.
+func (r *Responder) errorInfoFor(err error) (info ErrorInfo) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found bool
+	for _, e := range r.errorCatalog {
+		if errors.Is(err, e.target) {
+			info, found = e.info, true
+			break
+		}
+	}
+	if !found {
+		for _, e := range predefinedErrors {
+			if errors.Is(err, e.target) {
+				info = e.info
+				break
+			}
+		}
+	}
+
+	if info.DocsURL == "" && info.Code != "" && r.docsBaseURL != "" {
+		if u, err := url.JoinPath(r.docsBaseURL, info.Code); err == nil {
+			info.DocsURL = u
+		}
+	}
+
+	return
+}
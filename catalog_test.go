@@ -0,0 +1,90 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorPrecedence(t *testing.T) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		err      error
+		wantCode int
+		wantType MessageType
+		wantErr  string
+	}{
+		{
+			name: "first registered wins",
+			opts: []Option{
+				WithRegisteredErrorCode(errA, "codeA"),
+				WithRegisteredErrorCode(errB, "codeB"),
+				WithErrorMapping(errA, http.StatusNotFound, "typeA"),
+				WithErrorMapping(errB, http.StatusConflict, "typeB"),
+			},
+			err:      fmt.Errorf("%w: %w", errB, errA),
+			wantCode: http.StatusNotFound,
+			wantType: "typeA",
+			wantErr:  "codeA",
+		},
+		{
+			name: "same target registered twice",
+			opts: []Option{
+				WithRegisteredErrorCode(errA, "first"),
+				WithRegisteredErrorCode(errA, "second"),
+				WithErrorMapping(errA, http.StatusNotFound, "first"),
+				WithErrorMapping(errA, http.StatusConflict, "second"),
+			},
+			err:      fmt.Errorf("wrapped: %w", errA),
+			wantCode: http.StatusNotFound,
+			wantType: "first",
+			wantErr:  "first",
+		},
+		{
+			name:     "registered error overrides predefined",
+			opts:     []Option{WithRegisteredErrorCode(errNotFound, "missing")},
+			err:      errNotFound,
+			wantCode: http.StatusInternalServerError,
+			wantType: TypeError,
+			wantErr:  "missing",
+		},
+		{
+			name:     "predefined",
+			err:      errNotFound,
+			wantCode: http.StatusInternalServerError,
+			wantType: TypeError,
+			wantErr:  "notFound",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.opts...)
+
+			w := httptest.NewRecorder()
+			r.Error(tt.err, "", w)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d", w.Code, tt.wantCode)
+			}
+
+			var p Payload
+			if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+				t.Fatal(err)
+			}
+			if MessageType(p.Type) != tt.wantType {
+				t.Fatalf("got Type %q, want %q", p.Type, tt.wantType)
+			}
+			if p.ErrorData.Code != tt.wantErr {
+				t.Fatalf("got Code %q, want %q", p.ErrorData.Code, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package output
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// CBOR is an Encoder that encodes responses as CBOR (RFC 8949). The Payload, and the
+// Data stored in it, are encoded with the same field names as with JSON.
+var CBOR Encoder = &genericEncoder{
+	contentType: "application/cbor",
+	encode:      encodeCBOR,
+}
+
+// CBOR major types.
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+)
+
+// encodeCBOR writes the generic value g to w as CBOR.
+func encodeCBOR(w io.Writer, g interface{}) error {
+	bw := bufio.NewWriter(w)
+	if err := writeCBOR(bw, g); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// writeCBOR writes the generic value g as CBOR.
+func writeCBOR(w *bufio.Writer, g interface{}) error {
+	switch v := g.(type) {
+	case nil:
+		return w.WriteByte(0xf6)
+
+	case bool:
+		if v {
+			return w.WriteByte(0xf5)
+		}
+		return w.WriteByte(0xf4)
+
+	case json.Number:
+		return writeCBORNumber(w, v)
+
+	case string:
+		writeCBORHeader(w, cborText, uint64(len(v)))
+		_, err := w.WriteString(v)
+		return err
+
+	case []interface{}:
+		writeCBORHeader(w, cborArray, uint64(len(v)))
+		for _, e := range v {
+			if err := writeCBOR(w, e); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case genericMap:
+		writeCBORHeader(w, cborMap, uint64(len(v)))
+		for _, m := range v {
+			if err := writeCBOR(w, m.Key); err != nil {
+				return err
+			}
+			if err := writeCBOR(w, m.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("output: cannot encode %T as CBOR", g)
+	}
+}
+
+// writeCBORHeader writes the initial byte(s) of a data item, using the shortest
+// encoding of n.
+func writeCBORHeader(w *bufio.Writer, major byte, n uint64) {
+	major <<= 5
+
+	switch {
+	case n < 24:
+		w.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		w.WriteByte(major | 24)
+		w.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		w.WriteByte(major | 25)
+		w.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n <= math.MaxUint32:
+		w.WriteByte(major | 26)
+		w.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		w.WriteByte(major | 27)
+		w.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+}
+
+// writeCBORNumber writes a number as an integer if possible, otherwise as a float64.
+func writeCBORNumber(w *bufio.Writer, n json.Number) (err error) {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		if i >= 0 {
+			writeCBORHeader(w, cborUint, uint64(i))
+		} else {
+			writeCBORHeader(w, cborNegInt, uint64(-1-i))
+		}
+		return nil
+	}
+
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		writeCBORHeader(w, cborUint, u)
+		return nil
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return
+	}
+
+	w.WriteByte(0xfb)
+	_, err = w.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+	return
+}
@@ -0,0 +1,43 @@
+package output
+
+// WithVerboseErrors causes the chain of wrapped errors to be included in error
+// responses, see ErrorPayload's Chain. The chain is also included when debug is
+// enabled.
+//
+// This exposes internal details of errors to clients so it should only be used in
+// development or for internal services.
+func WithVerboseErrors(b bool) Option {
+	return func(r *Responder) {
+		r.verboseErrors = b
+	}
+}
+
+// errorChain returns the message of err and each error wrapped by err, walking
+// both errors.Unwrap and errors.Join style wrapping depth first. Nil is returned if
+// err does not wrap any errors since the chain would just repeat err.
+func errorChain(err error) (chain []string) {
+	var walk func(e error)
+	walk = func(e error) {
+		if e == nil {
+			return
+		}
+
+		chain = append(chain, e.Error())
+
+		switch u := e.(type) {
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				walk(inner)
+			}
+		}
+	}
+	walk(err)
+
+	if len(chain) < 2 {
+		return nil
+	}
+
+	return
+}
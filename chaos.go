@@ -0,0 +1,137 @@
+package output
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// HeaderChaos is set on responses altered by fault injection, see WithChaos. The
+// value is the Fault that was injected.
+const HeaderChaos = "X-Output-Chaos"
+
+// Fault is a failure injected into a response, see WithChaos.
+type Fault string
+
+// Faults that can be injected.
+const (
+	//FaultError replaces the response with an error.
+	FaultError Fault = "error"
+
+	//FaultDelay delays the response.
+	FaultDelay Fault = "delay"
+
+	//FaultTruncate sends only the first half of the response's body. The
+	//Content-Length header is set to the full length, so the client sees the
+	//connection close early, as if the server crashed mid-response. For Data
+	//streamed from a reader, see DataFoundReader, the length is not known so the
+	//body ends where the Data would start.
+	FaultTruncate Fault = "truncate"
+)
+
+// Defaults used when a ChaosConfig field is not set.
+const (
+	defaultChaosDelay        = time.Second
+	defaultChaosResponseCode = http.StatusServiceUnavailable
+	defaultChaosMsg          = "The service is temporarily unavailable, please try again."
+)
+
+// ChaosConfig configures fault injection, see WithChaos.
+type ChaosConfig struct {
+	//Percent is the percentage, from 0 to 100, of responses that faults are
+	//injected into.
+	Percent float64
+
+	//Faults are the faults to inject, one is chosen at random for each altered
+	//response. All faults are used if this is empty.
+	Faults []Fault
+
+	//Delay is the longest a response is delayed by with FaultDelay, the delay is
+	//random up to this duration. The default is 1 second.
+	Delay time.Duration
+
+	//ResponseCode is the HTTP status code of errors sent with FaultError. The
+	//default is 503.
+	ResponseCode int
+}
+
+// WithChaos injects faults into a percentage of responses so that client teams can
+// test their error handling against realistic failures. Injected errors are
+// Payloads, like any other error, with the serviceUnavailable code. Each altered
+// response has the HeaderChaos header set to the Fault that was injected.
+//
+// This is meant for tests and staging environments only, never enable this in
+// production.
+//
+//	r := output.New(output.WithChaos(output.ChaosConfig{
+//		Percent: 10,
+//		Faults:  []output.Fault{output.FaultError, output.FaultDelay},
+//	}))
+func WithChaos(c ChaosConfig) Option {
+	return func(r *Responder) {
+		if len(c.Faults) == 0 {
+			c.Faults = []Fault{FaultError, FaultDelay, FaultTruncate}
+		}
+		if c.Delay <= 0 {
+			c.Delay = defaultChaosDelay
+		}
+		if c.ResponseCode < http.StatusBadRequest {
+			c.ResponseCode = defaultChaosResponseCode
+		}
+
+		r.chaos = &c
+	}
+}
+
+// withTruncatedBody causes only the first half of the response's body to be sent,
+// see FaultTruncate.
+func withTruncatedBody() SendOption {
+	return func(o *sendOptions) {
+		o.truncateBody = true
+	}
+}
+
+// injectFault injects a fault into the response being sent, if fault injection is
+// enabled and the response is chosen at random. True is returned if a fault was
+// injected.
+func (r *Responder) injectFault(w http.ResponseWriter, p *Payload, responseCode *int, opts *[]SendOption) bool {
+	c := r.chaos
+	if c == nil || rand.Float64()*100 >= c.Percent {
+		return false
+	}
+
+	fault := c.Faults[rand.IntN(len(c.Faults))]
+	w.Header().Set(HeaderChaos, string(fault))
+
+	switch fault {
+	case FaultError:
+		*p = r.newPayload(false, TypeError, nil, r.newErrorPayload(errServiceUnavailable, defaultChaosMsg, w))
+		*responseCode = c.ResponseCode
+
+		//Per-response options are ignored since the response is no longer what
+		//the caller intended.
+		*opts = nil
+
+	case FaultDelay:
+		d := time.Duration(rand.Int64N(int64(c.Delay)) + 1)
+
+		//Stop waiting if the client gives up.
+		var done <-chan struct{}
+		if req := requestFrom(w); req != nil {
+			done = req.Context().Done()
+		}
+
+		t := time.NewTimer(d)
+		defer t.Stop()
+
+		select {
+		case <-t.C:
+		case <-done:
+		}
+
+	case FaultTruncate:
+		*opts = append(*opts, withTruncatedBody())
+	}
+
+	return true
+}
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestChaosTruncate(t *testing.T) {
+	tests := []struct {
+		name          string
+		send          func(r *Responder, w http.ResponseWriter) error
+		wantLength    bool
+		wantEmpty     bool
+		wantBodyStart string
+		wantBodyEnd   string
+	}{
+		{
+			name: "payload",
+			send: func(r *Responder, w http.ResponseWriter) error {
+				return r.DataFound(strings.Repeat("a", 100), w)
+			},
+			wantLength:    true,
+			wantBodyStart: `{"OK":true`,
+		},
+		{
+			name: "streamed data",
+			send: func(r *Responder, w http.ResponseWriter) error {
+				return r.DataFoundReader(strings.NewReader(`{"a":1}`), "application/json", w)
+			},
+			wantBodyEnd: `"Data":`,
+		},
+		{
+			name: "blob",
+			send: func(r *Responder, w http.ResponseWriter) error {
+				return r.SendBlob(bytes.NewReader([]byte("0123456789")), "text/plain", w)
+			},
+			wantLength:    true,
+			wantBodyStart: "01234",
+			wantBodyEnd:   "01234",
+		},
+		{
+			name: "raw stream of unknown length",
+			send: func(r *Responder, w http.ResponseWriter) error {
+				rd := io.MultiReader(strings.NewReader("0123456789"))
+				return r.DataFoundReader(rd, "text/plain", w, WithRaw())
+			},
+			wantEmpty: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(WithChaos(ChaosConfig{Percent: 100, Faults: []Fault{FaultTruncate}}))
+
+			w := httptest.NewRecorder()
+			if err := tt.send(r, w); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := w.Header().Get(HeaderChaos); got != string(FaultTruncate) {
+				t.Fatalf("got %s %q, want %q", HeaderChaos, got, FaultTruncate)
+			}
+
+			body := w.Body.String()
+			length := w.Header().Get("Content-Length")
+			if tt.wantLength {
+				n, err := strconv.Atoi(length)
+				if err != nil {
+					t.Fatalf("got Content-Length %q", length)
+				}
+				if len(body) != n/2 {
+					t.Fatalf("got %d bytes, want %d of %d", len(body), n/2, n)
+				}
+			} else if length != "" {
+				t.Fatalf("got Content-Length %q, want none", length)
+			}
+
+			if !strings.HasPrefix(body, tt.wantBodyStart) {
+				t.Fatalf("got body %q, want prefix %q", body, tt.wantBodyStart)
+			}
+			if !strings.HasSuffix(body, tt.wantBodyEnd) {
+				t.Fatalf("got body %q, want suffix %q", body, tt.wantBodyEnd)
+			}
+			if tt.wantEmpty && body != "" {
+				t.Fatalf("got body %q, want none", body)
+			}
+		})
+	}
+}
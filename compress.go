@@ -0,0 +1,187 @@
+package output
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultCompressionMinSize is the default size, in bytes, of a response below which
+// the response is not compressed. Compressing small responses wastes CPU and can
+// make the response larger.
+const defaultCompressionMinSize = 1024
+
+// Compressor compresses response bodies with a content coding, such as gzip. Gzip is
+// provided by this package, other content codings are provided by subpackages such
+// as outputbrotli and outputzstd.
+type Compressor interface {
+	//Encoding is the content coding, as used in the Accept-Encoding and
+	//Content-Encoding headers, for example "gzip".
+	Encoding() string
+
+	//Compress writes src, compressed, to dst. This must be safe for concurrent
+	//use.
+	Compress(dst io.Writer, src []byte) error
+}
+
+// Gzip is the Compressor for the gzip content coding.
+var Gzip Compressor = gzipCompressor{}
+
+// gzipCompressor compresses with gzip.
+type gzipCompressor struct{}
+
+// gzipWriters are reused since creating a gzip.Writer allocates a lot of memory.
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// Encoding implements Compressor.
+func (gzipCompressor) Encoding() string {
+	return "gzip"
+}
+
+// Compress implements Compressor.
+func (gzipCompressor) Compress(dst io.Writer, src []byte) (err error) {
+	gz := gzipWriters.Get().(*gzip.Writer)
+	defer gzipWriters.Put(gz)
+	gz.Reset(dst)
+
+	_, err = gz.Write(src)
+	if err != nil {
+		return
+	}
+
+	err = gz.Close()
+	return
+}
+
+// WithCompression causes responses of at least minSize bytes to be compressed when
+// the request's Accept-Encoding header allows it. If minSize is 0, a default of 1024
+// bytes is used. The ResponseWriter must carry the request, see Negotiate. Responses
+// are compressed with gzip unless other Compressors are provided with
+// WithCompressors.
+//
+// This is done as part of sending the response, versus using a middleware, so that
+// the response is not buffered twice. Responses with Data streamed from a reader,
+// see DataFoundReader, are not compressed.
+func WithCompression(minSize int) Option {
+	return func(r *Responder) {
+		if minSize <= 0 {
+			minSize = defaultCompressionMinSize
+		}
+
+		r.compressionMinSize = minSize
+	}
+}
+
+// WithCompressors sets the Compressors that responses can be compressed with, in
+// order of preference, when compression is enabled with WithCompression. The
+// Compressor used is the one the request's Accept-Encoding header gives the highest
+// quality, with ties going to the Compressor provided first. If no Compressors are
+// provided, Gzip is used.
+//
+// For example, to prefer brotli for browsers, which advertise br, and zstd for other
+// services, which can be built to advertise only zstd, while still supporting gzip:
+//
+//	output.WithCompressors(outputbrotli.Compressor, outputzstd.Compressor, output.Gzip)
+func WithCompressors(cs ...Compressor) Option {
+	return func(r *Responder) {
+		if len(cs) == 0 {
+			cs = []Compressor{Gzip}
+		}
+
+		r.compressors = cs
+	}
+}
+
+// compress returns body compressed, and the content coding used, if the response
+// being sent to w should be compressed. Otherwise, body is returned as is with a
+// blank content coding.
+func (r *Responder) compress(w http.ResponseWriter, body []byte) (out []byte, coding string) {
+	if r.compressionMinSize <= 0 {
+		return body, ""
+	}
+
+	//Whether or not the response is compressed depends on the request's
+	//Accept-Encoding header so caches need to know.
+	addVary(w.Header(), "Accept-Encoding")
+
+	if len(body) < r.compressionMinSize {
+		return body, ""
+	}
+
+	req := requestFrom(w)
+	if req == nil {
+		return body, ""
+	}
+
+	c := negotiateCompressor(req.Header.Get("Accept-Encoding"), r.compressors)
+	if c == nil {
+		return body, ""
+	}
+
+	var b bytes.Buffer
+	if err := c.Compress(&b, body); err != nil {
+		r.log(w, "output.compress: could not compress", "encoding", c.Encoding(), "error", err)
+		return body, ""
+	}
+
+	return b.Bytes(), c.Encoding()
+}
+
+// negotiateCompressor returns the Compressor with the highest quality in an
+// Accept-Encoding header, or nil if none are acceptable.
+func negotiateCompressor(header string, cs []Compressor) Compressor {
+	if header == "" {
+		return nil
+	}
+
+	var (
+		best  Compressor
+		bestQ float64
+	)
+	for _, c := range cs {
+		//Earlier compressors win ties since they are preferred.
+		if q := acceptEncodingQ(header, c.Encoding()); q > bestQ {
+			best, bestQ = c, q
+		}
+	}
+
+	return best
+}
+
+// acceptEncodingQ returns the quality of coding in an Accept-Encoding header. 0 is
+// returned if coding is not acceptable.
+func acceptEncodingQ(header, coding string) float64 {
+	q, found := 0.0, false
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != coding && name != "*" {
+			continue
+		}
+
+		partQ := 1.0
+		if k, v, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				partQ = parsed
+			}
+		}
+
+		//An exact match takes precedence over the wildcard.
+		if name == coding {
+			return partQ
+		}
+		if !found {
+			q, found = partQ, true
+		}
+	}
+
+	return q
+}
@@ -0,0 +1,15 @@
+package output
+
+// WithContentLength causes responses to be encoded into a buffer so that the
+// Content-Length header can be set before the response is written. Some proxies, and
+// clients that reuse connections, require the Content-Length header. Otherwise,
+// responses are usually encoded directly to the ResponseWriter and net/http only sets
+// the Content-Length header for small responses.
+//
+// Responses with Data streamed from a reader, see DataFoundReader, never have a
+// Content-Length header since the length is not known until the Data is read.
+func WithContentLength(b bool) Option {
+	return func(r *Responder) {
+		r.contentLength = b
+	}
+}
@@ -0,0 +1,107 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrSchemaMismatch is returned when Data does not match the JSON Schema registered
+// for the response's message type. An HTTP status 500 error Payload is sent in place
+// of the response.
+var ErrSchemaMismatch = errors.New("output: data does not match schema")
+
+// errSchemaMismatch is the error sent in place of a response whose Data does not
+// match its schema.
+var errSchemaMismatch = errors.New("data does not match schema")
+
+// defaultSchemaMismatchMsg is the Message sent in place of a response whose Data
+// does not match its schema.
+const defaultSchemaMismatchMsg = "The response did not match its schema."
+
+// WithSchema registers the JSON Schema that Data must match for responses with the
+// message type msgType when creating a Responder. See Responder.RegisterSchema.
+func WithSchema(msgType MessageType, s *JSONSchema) Option {
+	return func(r *Responder) {
+		r.RegisterSchema(msgType, s)
+	}
+}
+
+// WithSchemaValidation turns validation of Data against the schemas registered with
+// WithSchema, or RegisterSchema, on or off. Validation is also done for any response
+// sent while debug is enabled. Validation is meant for tests and debugging since
+// each response's Data is encoded an extra time to validate it.
+func WithSchemaValidation(b bool) Option {
+	return func(r *Responder) {
+		r.schemaValidation = b
+	}
+}
+
+// RegisterSchema registers the JSON Schema that Data must match for responses with
+// the message type msgType sent by the default Responder. See
+// Responder.RegisterSchema.
+func RegisterSchema(msgType MessageType, s *JSONSchema) {
+	std.RegisterSchema(msgType, s)
+}
+
+// RegisterSchema registers the JSON Schema that Data must match for responses with
+// the message type msgType. This catches accidental changes to the shape of
+// responses, for example a renamed field, before clients do.
+//
+// When schema validation is enabled, see WithSchemaValidation, and Data does not
+// match its schema the mismatches are logged as errors, an HTTP status 500 error
+// Payload listing the mismatches is sent in place of the response, and
+// ErrSchemaMismatch is returned.
+func (r *Responder) RegisterSchema(msgType MessageType, s *JSONSchema) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.schemas == nil {
+		r.schemas = make(map[MessageType]*JSONSchema)
+	}
+
+	r.schemas[msgType] = s
+}
+
+// withoutSchema skips schema validation, for the error sent in place of a response
+// whose Data does not match its schema.
+func withoutSchema() SendOption {
+	return func(o *sendOptions) {
+		o.skipSchema = true
+	}
+}
+
+// checkSchema validates p's Data against the schema registered for p's message
+// type, if schema validation is enabled. If Data does not match, an error is sent
+// in place of the response and an error wrapping ErrSchemaMismatch is returned.
+func (r *Responder) checkSchema(p *Payload, w http.ResponseWriter, o sendOptions) (err error) {
+	//Data read from a reader is not validated since it has not been read yet.
+	if o.skipSchema || o.stream != nil || (!r.schemaValidation && !r.debugging(w)) {
+		return
+	}
+
+	r.mu.RLock()
+	s := r.schemas[MessageType(p.Type)]
+	r.mu.RUnlock()
+
+	if s == nil {
+		return
+	}
+
+	verr := s.Validate(p.Data)
+	if verr == nil {
+		return
+	}
+
+	r.logger.Error("output.send: data does not match schema", "type", p.Type, "error", verr)
+
+	ep := r.newErrorPayload(errSchemaMismatch, defaultSchemaMismatchMsg, w)
+	ep.Details = map[string]interface{}{
+		"Type":       p.Type,
+		"Mismatches": verr.Error(),
+	}
+	r.buildAndSend(false, TypeError, nil, ep, w, http.StatusInternalServerError, withoutSchema())
+
+	err = fmt.Errorf("%w: %s", ErrSchemaMismatch, verr)
+	return
+}
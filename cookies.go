@@ -0,0 +1,69 @@
+package output
+
+import "net/http"
+
+// CookieFunc returns cookies to set on the response to req, for example a session
+// cookie with a refreshed expiration. req is nil unless the ResponseWriter carries
+// the request, see Negotiate.
+type CookieFunc func(req *http.Request) []*http.Cookie
+
+// WithDefaultCookie sets a cookie on every response sent by the Responder.
+func WithDefaultCookie(c *http.Cookie) Option {
+	return func(r *Responder) {
+		r.defaultCookies = append(r.defaultCookies, c)
+	}
+}
+
+// WithCookieFunc sets the cookies returned by fn on every response sent by the
+// Responder. This is used for cookies that differ per request, such as refreshing a
+// session cookie, so that authentication flows don't need to wrap every handler.
+func WithCookieFunc(fn CookieFunc) Option {
+	return func(r *Responder) {
+		r.cookieFuncs = append(r.cookieFuncs, fn)
+	}
+}
+
+// WithCookie sets a cookie on the response. A cookie with the same name, and path,
+// as a cookie set by WithDefaultCookie or WithCookieFunc replaces that cookie.
+func WithCookie(c *http.Cookie) SendOption {
+	return func(o *sendOptions) {
+		o.cookies = append(o.cookies, c)
+	}
+}
+
+// setCookies sets the Responder's default cookies, and the cookies provided with
+// SendOptions, on the response. Later cookies replace earlier cookies with the same
+// name and path.
+func (r *Responder) setCookies(w http.ResponseWriter, o sendOptions) {
+	if len(r.defaultCookies) == 0 && len(r.cookieFuncs) == 0 && len(o.cookies) == 0 {
+		return
+	}
+
+	cookies := append([]*http.Cookie(nil), r.defaultCookies...)
+	if len(r.cookieFuncs) > 0 {
+		req := requestFrom(w)
+		for _, fn := range r.cookieFuncs {
+			cookies = append(cookies, fn(req)...)
+		}
+	}
+	cookies = append(cookies, o.cookies...)
+
+	for i, c := range cookies {
+		if c == nil || replacedCookie(c, cookies[i+1:]) {
+			continue
+		}
+
+		http.SetCookie(w, c)
+	}
+}
+
+// replacedCookie reports if a cookie in later has the same name and path as c.
+func replacedCookie(c *http.Cookie, later []*http.Cookie) bool {
+	for _, l := range later {
+		if l != nil && l.Name == c.Name && l.Path == c.Path {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,36 @@
+package output
+
+import "net/http"
+
+// CreatedPayload is the Data sent by Created.
+type CreatedPayload struct {
+	//ID is the ID of the just created resource.
+	ID int64
+
+	//Resource is an optional representation of the just created resource.
+	Resource interface{} `json:",omitempty"`
+}
+
+// Created is used when a request resulted in a new resource being created. This is
+// a more REST-correct alternative to InsertOK: an HTTP status 201 is returned, the
+// Location header is set to location (if provided), and Data holds the new
+// resource's ID and, optionally, a representation of the resource.
+func Created(id int64, location string, data interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.Created(id, location, data, w, opts...)
+	return
+}
+
+// Created sends a 201 for a just created resource. See the package-level Created.
+func (r *Responder) Created(id int64, location string, data interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	if location != "" {
+		opts = append([]SendOption{WithHeader("Location", location)}, opts...)
+	}
+
+	cp := CreatedPayload{
+		ID:       id,
+		Resource: data,
+	}
+
+	err = r.buildAndSend(true, TypeCreated, cp, ErrorPayload{}, w, http.StatusCreated, opts...)
+	return
+}
@@ -0,0 +1,188 @@
+package output
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidCSVData is returned by DataFoundCSV when rows is not a slice of structs.
+var ErrInvalidCSVData = errors.New("output: CSV data must be a slice of structs")
+
+// defaultCSVFilename is the name of the file CSV data is downloaded as if a name is
+// not provided with WithFilename.
+const defaultCSVFilename = "export.csv"
+
+// WithFilename sets the name of the file the response is downloaded as, via the
+// Content-Disposition header. This is used with DataFoundCSV, SendBlob, and other
+// raw responses.
+func WithFilename(name string) SendOption {
+	return func(o *sendOptions) {
+		o.filename = name
+	}
+}
+
+// DataFoundCSV is used to send back tabular data as a CSV file for download, for
+// example for "export to spreadsheet" features. rows must be a slice of structs, or
+// pointers to structs. The first row of the CSV is the header row, the names of the
+// columns are taken from each field's csv struct tag, json struct tag, or name.
+// Fields tagged with `csv:"-"` are skipped.
+//
+// The CSV is sent without the Payload. Errors should still be sent with Error, and
+// related funcs, so that clients get the usual Payload when an export fails. The
+// file is named export.csv unless a name is provided with WithFilename.
+func DataFoundCSV(rows interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.DataFoundCSV(rows, w, opts...)
+	return
+}
+
+// DataFoundCSV sends rows as a CSV file. See the package-level DataFoundCSV.
+func (r *Responder) DataFoundCSV(rows interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	b, err := encodeCSV(rows)
+	if err != nil {
+		r.log(w, "output.DataFoundCSV: could not encode CSV", "error", err)
+		return
+	}
+
+	opts = append([]SendOption{WithFilename(defaultCSVFilename)}, opts...)
+	err = r.SendBlob(bytes.NewReader(b), "text/csv; charset=UTF-8", w, opts...)
+	return
+}
+
+// csvColumn is a column of CSV data.
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+// encodeCSV returns rows encoded as CSV with a header row.
+func encodeCSV(rows interface{}) (b []byte, err error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		err = ErrInvalidCSVData
+		return
+	}
+
+	t := v.Type().Elem()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		err = ErrInvalidCSVData
+		return
+	}
+
+	columns := csvColumns(t)
+
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+
+	record := make([]string, len(columns))
+	for i, c := range columns {
+		record[i] = c.name
+	}
+	if err = cw.Write(record); err != nil {
+		return
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		row := v.Index(i)
+		if row.Kind() == reflect.Pointer {
+			if row.IsNil() {
+				continue
+			}
+			row = row.Elem()
+		}
+
+		for j, c := range columns {
+			//A field promoted from a nil embedded pointer is an empty cell.
+			field, fieldErr := row.FieldByIndexErr(c.index)
+			if fieldErr != nil {
+				record[j] = ""
+				continue
+			}
+			record[j] = csvValue(field)
+		}
+		if err = cw.Write(record); err != nil {
+			return
+		}
+	}
+
+	cw.Flush()
+	err = cw.Error()
+	b = buf.Bytes()
+	return
+}
+
+// csvColumns returns the columns for the exported fields of t.
+func csvColumns(t reflect.Type) (columns []csvColumn) {
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("csv"); ok {
+			tag, _, _ = strings.Cut(tag, ",")
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		} else if tag, ok := f.Tag.Lookup("json"); ok {
+			tag, _, _ = strings.Cut(tag, ",")
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		columns = append(columns, csvColumn{name: name, index: f.Index})
+	}
+
+	return
+}
+
+// csvValue returns v formatted for a CSV cell.
+func csvValue(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	//Fields promoted from unexported embedded structs cannot be used as an
+	//interface, the value is formatted as is.
+	if !v.CanInterface() {
+		return fmt.Sprint(v)
+	}
+
+	switch i := v.Interface().(type) {
+	case encoding.TextMarshaler:
+		b, err := i.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	case fmt.Stringer:
+		return i.String()
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// contentDisposition returns the Content-Disposition header for downloading a file
+// named filename.
+func contentDisposition(filename string) string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
@@ -0,0 +1,150 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type csvAudit struct {
+	CreatedBy string
+}
+
+type csvRow struct {
+	ID       int    `csv:"id"`
+	Name     string `json:"name,omitempty"`
+	Password string `csv:"-"`
+	Secret   string `json:"-"`
+	Note     *string
+	When     time.Time
+	*csvAudit
+}
+
+func TestEncodeCSV(t *testing.T) {
+	note := "hi, there"
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		rows    interface{}
+		want    string
+		wantErr error
+	}{
+		{
+			name: "header naming and skipped fields",
+			rows: []csvRow{{ID: 1, Name: "a", Password: "p", Secret: "s"}},
+			want: "id,name,Note,When,CreatedBy\n1,a,,0001-01-01T00:00:00Z,\n",
+		},
+		{
+			name: "values",
+			rows: []csvRow{{ID: 2, Name: "b", Note: &note, When: when, csvAudit: &csvAudit{CreatedBy: "c"}}},
+			want: "id,name,Note,When,CreatedBy\n2,b,\"hi, there\",2024-01-02T03:04:05Z,c\n",
+		},
+		{
+			name: "pointer rows",
+			rows: []*csvRow{{ID: 3}, nil, {ID: 4}},
+			want: "id,name,Note,When,CreatedBy\n3,,,0001-01-01T00:00:00Z,\n4,,,0001-01-01T00:00:00Z,\n",
+		},
+		{
+			name: "empty",
+			rows: []csvRow{},
+			want: "id,name,Note,When,CreatedBy\n",
+		},
+		{
+			name:    "not a slice",
+			rows:    csvRow{},
+			wantErr: ErrInvalidCSVData,
+		},
+		{
+			name:    "not structs",
+			rows:    []int{1, 2},
+			wantErr: ErrInvalidCSVData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := encodeCSV(tt.rows)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if string(b) != tt.want {
+				t.Fatalf("got %q, want %q", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestDataFoundCSV(t *testing.T) {
+	tests := []struct {
+		name            string
+		rows            interface{}
+		opts            []SendOption
+		wantErr         error
+		wantContentType string
+		wantDisposition string
+	}{
+		{
+			name:            "default filename",
+			rows:            []csvRow{{ID: 1}},
+			wantContentType: "text/csv; charset=UTF-8",
+			wantDisposition: `attachment; filename=export.csv`,
+		},
+		{
+			name:            "filename",
+			rows:            []csvRow{{ID: 1}},
+			opts:            []SendOption{WithFilename("users.csv")},
+			wantContentType: "text/csv; charset=UTF-8",
+			wantDisposition: `attachment; filename=users.csv`,
+		},
+		{
+			name:            "invalid rows",
+			rows:            "rows",
+			wantErr:         ErrInvalidCSVData,
+			wantContentType: "application/json; charset=UTF-8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New()
+			w := httptest.NewRecorder()
+
+			err := r.DataFoundCSV(tt.rows, w, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			//Nothing is sent when the rows cannot be encoded so that the error can
+			//be sent, as a JSON Payload, with Error.
+			if err != nil {
+				if w.Body.Len() != 0 {
+					t.Fatalf("got body %q, want empty", w.Body.String())
+				}
+
+				r.Error(err, "", w)
+
+				var p Payload
+				if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+					t.Fatal(err)
+				}
+				if p.OK || p.ErrorData.Error != ErrInvalidCSVData.Error() {
+					t.Fatalf("got payload %+v", p)
+				}
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Fatalf("got Content-Type %q, want %q", got, tt.wantContentType)
+			}
+			if got := w.Header().Get("Content-Disposition"); got != tt.wantDisposition {
+				t.Fatalf("got Content-Disposition %q, want %q", got, tt.wantDisposition)
+			}
+			if err == nil && w.Code != http.StatusOK {
+				t.Fatalf("got status %d", w.Code)
+			}
+		})
+	}
+}
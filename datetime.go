@@ -0,0 +1,29 @@
+package output
+
+// WithOmitDatetime causes the Datetime field to be omitted from every Payload, for
+// example for bandwidth-sensitive mobile APIs.
+func WithOmitDatetime(b bool) Option {
+	return func(r *Responder) {
+		r.omitDatetime = b
+	}
+}
+
+// WithDatetimeField sets the name the Datetime field is encoded as, for example
+// "timestamp", to match an existing client contract when migrating a service onto
+// this package. This applies to the JSON, XML, MessagePack, and CBOR Encoders, and
+// any other Encoder based on JSON. Protobuf uses the field defined in its schema.
+func WithDatetimeField(name string) Option {
+	return func(r *Responder) {
+		r.datetimeField = name
+	}
+}
+
+// setEnvelopeKeys omits the Payload's Datetime field, and sets how the Payload's
+// keys are encoded, per the Responder's configuration.
+func (r *Responder) setEnvelopeKeys(p *Payload) {
+	if r.omitDatetime {
+		p.Datetime = ""
+	}
+
+	p.keys = r.keys
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// WithDebugToken turns on debug, for a single response, when the request's header
+// matches token. This allows troubleshooting in production, with verbose error
+// payloads and diagnostic logging for just the request being investigated, without
+// enabling debug for every request.
+//
+//	r := output.New(output.WithDebugToken("X-Debug-Token", os.Getenv("DEBUG_TOKEN")))
+//
+// The ResponseWriter must carry the request, see Negotiate. The token should be a
+// long random secret since it exposes internal details of errors. A blank token
+// disables this.
+func WithDebugToken(header, token string) Option {
+	return func(r *Responder) {
+		r.debugHeader = http.CanonicalHeaderKey(header)
+		r.debugToken = token
+	}
+}
+
+// debugging reports if debug is enabled for the response being sent to w, either
+// because debug is enabled for the Responder or the request carried by w provided
+// the debug token. w may be nil.
+func (r *Responder) debugging(w http.ResponseWriter) bool {
+	if r.debug.Load() {
+		return true
+	}
+
+	if r.debugToken == "" || w == nil {
+		return false
+	}
+
+	req := requestFrom(w)
+	if req == nil {
+		return false
+	}
+
+	got := req.Header.Get(r.debugHeader)
+	return subtle.ConstantTimeCompare([]byte(got), []byte(r.debugToken)) == 1
+}
@@ -0,0 +1,57 @@
+package output
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WarningDeprecated is the Code of the Warning added by WithDeprecation.
+const WarningDeprecated = "deprecated"
+
+// WithDeprecation marks the response as coming from a deprecated endpoint so that
+// clients receive machine-readable notice before the endpoint is removed. The
+// Deprecation header is set and a Warning is added to the Payload. If sunset is not
+// zero, the Sunset header (RFC 8594) is set to when the endpoint will be removed. If
+// link is provided, a Link header with the "deprecation" relation is added pointing
+// to documentation about the deprecation, such as a migration guide.
+//
+//	output.DataFound(data, w, output.WithDeprecation(sunset, "https://developer.example.com/migrate/v2"))
+func WithDeprecation(sunset time.Time, link string) SendOption {
+	return func(o *sendOptions) {
+		msg := "This endpoint is deprecated"
+		if !sunset.IsZero() {
+			msg += " and will be removed on " + sunset.UTC().Format(time.RFC3339)
+		}
+		msg += "."
+		if link != "" {
+			msg += " See " + link + "."
+		}
+
+		WithWarning(WarningDeprecated, msg)(o)
+		WithHeader("Deprecation", "true")(o)
+		if !sunset.IsZero() {
+			WithHeader("Sunset", sunset.UTC().Format(http.TimeFormat))(o)
+		}
+
+		if link != "" {
+			//Added separately from other headers so that existing Link headers,
+			//such as for pagination, are not replaced.
+			o.linkHeader = append(o.linkHeader, "<"+link+`>; rel="deprecation"`)
+		}
+	}
+}
+
+// addLinks adds the Link header values provided by SendOptions to the response.
+func (o sendOptions) addLinks(w http.ResponseWriter) {
+	if len(o.linkHeader) == 0 {
+		return
+	}
+
+	links := o.linkHeader
+	if existing := w.Header().Get("Link"); existing != "" {
+		links = append([]string{existing}, links...)
+	}
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
@@ -0,0 +1,26 @@
+package output
+
+import "net/http"
+
+// ErrorWithDetails is similar to Error but allows for returning structured context
+// about the error, such as the ID of conflicting data or the allowed values of a
+// field, in ErrorData's Details. This is used instead of abusing Data on error
+// responses.
+func ErrorWithDetails(errType error, errMsg string, details map[string]interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorWithDetails(errType, errMsg, details, w, opts...)
+	return
+}
+
+// ErrorWithDetails sends an error with details. See the package-level
+// ErrorWithDetails.
+func (r *Responder) ErrorWithDetails(errType error, errMsg string, details map[string]interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	code, msgType := r.mapError(errType)
+
+	ep := r.newErrorPayload(errType, errMsg, w)
+	ep.Details = details
+
+	r.log(w, "output.ErrorWithDetails", "type", msgType, "status", code, "error", errType, "message", errMsg, "details", details)
+
+	err = r.buildAndSend(false, msgType, nil, ep, w, code, opts...)
+	return
+}
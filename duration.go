@@ -0,0 +1,37 @@
+package output
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// requestStartKey is the context key the request's start time is stored under.
+type requestStartKey struct{}
+
+// TimingMiddleware records the time each request started so that the time spent
+// handling the request is included as the Payload's DurationMS. The duration is
+// included when responses are sent with SendCtx, ErrorCtx, or with a ResponseWriter
+// that carries the request (see Negotiate).
+//
+// Use this as the outermost middleware so the duration covers as much of handling
+// the request as possible.
+func TimingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), requestStartKey{}, time.Now())
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// RequestStartFrom returns the time the request started, as stored in ctx by
+// TimingMiddleware. False is returned if ctx does not have a start time.
+func RequestStartFrom(ctx context.Context) (start time.Time, ok bool) {
+	start, ok = ctx.Value(requestStartKey{}).(time.Time)
+	return
+}
+
+// durationMS returns the number of milliseconds since start, rounded to the nearest
+// microsecond.
+func durationMS(start time.Time) float64 {
+	return float64(time.Since(start).Round(time.Microsecond)) / float64(time.Millisecond)
+}
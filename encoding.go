@@ -0,0 +1,47 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+)
+
+// Encoder encodes a response body in a specific format. Encoders are provided to a
+// Responder with WithEncoders and chosen per request based on the request's Accept
+// header (see Negotiate).
+type Encoder interface {
+	//ContentType is the value of the Content-Type header sent with responses
+	//encoded by this Encoder.
+	ContentType() string
+
+	//Encode writes the encoded v to w.
+	Encode(w io.Writer, v interface{}) error
+}
+
+// JSON is the default Encoder.
+var JSON Encoder = jsonEncoder{}
+
+// jsonEncoder encodes responses as JSON.
+type jsonEncoder struct{}
+
+// ContentType implements Encoder.
+func (jsonEncoder) ContentType() string {
+	return "application/json; charset=UTF-8"
+}
+
+// Encode implements Encoder. The encoded value is followed by a newline.
+func (jsonEncoder) Encode(w io.Writer, v interface{}) (err error) {
+	err = json.NewEncoder(w).Encode(v)
+	return
+}
+
+// mediaType returns the media type of an Encoder's content type, without any
+// parameters, for matching against an Accept header.
+func mediaType(enc Encoder) string {
+	mt, _, err := mime.ParseMediaType(enc.ContentType())
+	if err != nil {
+		return enc.ContentType()
+	}
+
+	return mt
+}
@@ -0,0 +1,48 @@
+package output
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Headers used to send the Payload's fields with raw responses, see
+// WithEnvelopeHeaders.
+const (
+	HeaderOutputOK        = "X-Output-OK"
+	HeaderOutputType      = "X-Output-Type"
+	HeaderOutputDatetime  = "X-Output-Datetime"
+	HeaderOutputRequestID = "X-Output-Request-ID"
+	HeaderOutputSequence  = "X-Output-Sequence"
+)
+
+// WithEnvelopeHeaders causes the Payload's OK, Type, Datetime, RequestID, and
+// Sequence to be sent as X-Output-* headers with raw responses, such as those sent
+// with WithRaw, SendRaw, or SendBlob. This allows clients, and proxies, to handle
+// binary responses, such as images or PDFs, the same way as other responses even
+// though the Payload is not sent.
+func WithEnvelopeHeaders(b bool) Option {
+	return func(r *Responder) {
+		r.envelopeHeaders = b
+	}
+}
+
+// setEnvelopeHeaders sets the X-Output-* headers from p.
+func (r *Responder) setEnvelopeHeaders(w http.ResponseWriter, p *Payload) {
+	if !r.envelopeHeaders {
+		return
+	}
+
+	h := w.Header()
+	h.Set(HeaderOutputOK, strconv.FormatBool(p.OK))
+	h.Set(HeaderOutputType, p.Type)
+
+	if p.Datetime != "" {
+		h.Set(HeaderOutputDatetime, p.Datetime)
+	}
+	if p.RequestID != "" {
+		h.Set(HeaderOutputRequestID, p.RequestID)
+	}
+	if p.Sequence > 0 {
+		h.Set(HeaderOutputSequence, strconv.FormatUint(p.Sequence, 10))
+	}
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"errors"
+	"net/http"
+)
+
+// StatusCoder is implemented by errors that define the HTTP status code to respond
+// with when the error is provided to Error or ErrorWithID.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// MessageTyper is implemented by errors that define the message type to respond
+// with when the error is provided to Error or ErrorWithID.
+type MessageTyper interface {
+	MessageType() string
+}
+
+// errorMapping maps an error to the HTTP status code and message type used when the
+// error is provided to Error.
+type errorMapping struct {
+	target       error
+	responseCode int
+	msgType      MessageType
+}
+
+// MapError registers the HTTP status code and message type used by Error and
+// ErrorWithID on the default Responder when the provided error matches target. See
+// Responder.MapError.
+//
+//	output.MapError(sql.ErrNoRows, http.StatusNotFound, "notFound")
+func MapError(target error, responseCode int, msgType MessageType) {
+	std.MapError(target, responseCode, msgType)
+}
+
+// WithErrorMapping registers an error mapping when creating a Responder. See
+// Responder.MapError.
+func WithErrorMapping(target error, responseCode int, msgType MessageType) Option {
+	return func(r *Responder) {
+		r.MapError(target, responseCode, msgType)
+	}
+}
+
+// MapError registers the HTTP status code and message type used by Error and
+// ErrorWithID when the provided error matches target. Matching is done with
+// errors.Is so wrapped errors are matched as well. If msgType is blank, the
+// predefined error message type is used.
+//
+// Mappings are checked in the order they were registered, the first match is used,
+// the same as with RegisterError. If no mapping matches, the Responder's error status code is used. Errors that
+// implement StatusCoder or MessageTyper take precedence over mappings.
+func (r *Responder) MapError(target error, responseCode int, msgType MessageType) {
+	if msgType == "" {
+		msgType = TypeError
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errorMappings = append(r.errorMappings, errorMapping{
+		target:       target,
+		responseCode: responseCode,
+		msgType:      msgType,
+	})
+}
+
+// mapError returns the HTTP status code and message type to use for err.
+//
+// If err, or an error it wraps, implements StatusCoder or MessageTyper the values
+// returned by those interfaces are used. Otherwise, the registered error mappings
+// are used. If nothing applies, the Responder's error status code and the
+// predefined error message type are used.
+func (r *Responder) mapError(err error) (responseCode int, msgType MessageType) {
+	responseCode, msgType = r.errorCode, TypeError
+
+	r.mu.RLock()
+	for _, m := range r.errorMappings {
+		if errors.Is(err, m.target) {
+			responseCode, msgType = m.responseCode, m.msgType
+			break
+		}
+	}
+	r.mu.RUnlock()
+
+	//Errors that define their own response details take precedence.
+	var sc StatusCoder
+	if errors.As(err, &sc) {
+		if code := sc.StatusCode(); code >= http.StatusContinue {
+			responseCode = code
+		}
+	}
+
+	var mt MessageTyper
+	if errors.As(err, &mt) {
+		if t := mt.MessageType(); t != "" {
+			msgType = MessageType(t)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,54 @@
+package output
+
+import "bytes"
+
+// WithEscapeHTML sets whether the characters <, >, and & are escaped in JSON
+// responses, as json.Marshal does by default. Escaping is safe when JSON is
+// embedded in HTML but mangles URLs and rich text for some clients. The default is
+// true.
+func WithEscapeHTML(b bool) Option {
+	return func(r *Responder) {
+		r.escapeHTML = b
+	}
+}
+
+// htmlEscapes are the escape sequences json.Marshal uses for HTML characters.
+var htmlEscapes = map[string]byte{
+	"003c": '<',
+	"003e": '>',
+	"0026": '&',
+}
+
+// unescapeHTML replaces the escape sequences json.Marshal uses for HTML characters
+// in j with the characters themselves. This is done after encoding, versus using
+// json.Encoder's SetEscapeHTML, since types implementing json.Marshaler, including
+// Payload, escape HTML characters regardless.
+func unescapeHTML(j []byte) []byte {
+	if !bytes.Contains(j, []byte(`\u00`)) {
+		return j
+	}
+
+	out := make([]byte, 0, len(j))
+	for i := 0; i < len(j); i++ {
+		c := j[i]
+		if c != '\\' || i+1 >= len(j) {
+			out = append(out, c)
+			continue
+		}
+
+		//Escape sequences are copied as a pair so that an escaped backslash
+		//followed by "u003c" is not mistaken for an escaped "<".
+		if j[i+1] == 'u' && i+5 < len(j) {
+			if r, ok := htmlEscapes[string(j[i+2:i+6])]; ok {
+				out = append(out, r)
+				i += 5
+				continue
+			}
+		}
+
+		out = append(out, c, j[i+1])
+		i++
+	}
+
+	return out
+}
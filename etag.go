@@ -0,0 +1,108 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// WithETags causes an ETag to be set on successful responses to GET and HEAD
+// requests. If the request's If-None-Match header matches the ETag, an HTTP status
+// 304 is sent without a body instead of the response. This saves bandwidth for
+// clients that poll an endpoint for data that rarely changes. The ResponseWriter
+// must carry the request, see Negotiate.
+//
+// The ETag is a weak ETag calculated from the Payload's OK, Type, Data, ErrorData,
+// Errors, Links, Warnings, and Meta. Fields that differ for every response, such as
+// Datetime and RequestID, are not used. Use WithETag to provide your own ETag, such
+// as a version number or last modified time, to avoid the cost of hashing.
+func WithETags(b bool) Option {
+	return func(r *Responder) {
+		r.etags = b
+	}
+}
+
+// WithETag sets the ETag of the response to version. version is quoted if it is not
+// already. This is used instead of calculating an ETag, see WithETags, and is used
+// even if WithETags is not enabled.
+//
+//	output.DataFound(user, w, output.WithETag(strconv.Itoa(user.Version)))
+func WithETag(version string) SendOption {
+	return func(o *sendOptions) {
+		if version != "" && !strings.HasSuffix(version, `"`) {
+			version = `"` + version + `"`
+		}
+
+		o.etag = version
+	}
+}
+
+// etagFields are the fields of a Payload used to calculate an ETag.
+type etagFields struct {
+	OK        bool
+	Type      string
+	Data      interface{}
+	ErrorData ErrorPayload
+	Errors    []ErrorPayload
+	Links     *Links
+	Warnings  []Warning
+	Meta      map[string]interface{}
+}
+
+// etag returns the ETag for the response, or a blank string if the response should
+// not have an ETag. ETags are only calculated for responses to GET and HEAD requests
+// since the response to other requests cannot be cached.
+func (r *Responder) etag(p *Payload, o sendOptions, responseCode int, req *http.Request) string {
+	if o.etag != "" {
+		return o.etag
+	}
+
+	if !r.etags || !p.OK || responseCode != http.StatusOK || o.stream != nil {
+		return ""
+	}
+	if req == nil || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		return ""
+	}
+
+	j, err := json.Marshal(etagFields{
+		OK:        p.OK,
+		Type:      p.Type,
+		Data:      p.Data,
+		ErrorData: p.ErrorData,
+		Errors:    p.Errors,
+		Links:     p.Links,
+		Warnings:  p.Warnings,
+		Meta:      p.Meta,
+	})
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(j)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified reports if req's If-None-Match header matches etag. Weak comparison is
+// used, as required for If-None-Match.
+func notModified(req *http.Request, etag string) bool {
+	if req == nil || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		return false
+	}
+
+	inm := req.Header.Get("If-None-Match")
+	if inm == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(inm, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
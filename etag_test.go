@@ -0,0 +1,212 @@
+package output
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestETags(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		ifNoneMatch string
+		opts        []SendOption
+		send        func(r *Responder, w http.ResponseWriter, opts ...SendOption)
+		wantCode    int
+		wantETag    bool
+		wantBody    bool
+	}{
+		{
+			name:     "get",
+			method:   http.MethodGet,
+			wantCode: http.StatusOK,
+			wantETag: true,
+			wantBody: true,
+		},
+		{
+			name:     "head",
+			method:   http.MethodHead,
+			wantCode: http.StatusOK,
+			wantETag: true,
+		},
+		{
+			name:        "get matching",
+			method:      http.MethodGet,
+			ifNoneMatch: "match",
+			wantCode:    http.StatusNotModified,
+			wantETag:    true,
+		},
+		{
+			name:        "get matching any",
+			method:      http.MethodGet,
+			ifNoneMatch: "*",
+			wantCode:    http.StatusNotModified,
+			wantETag:    true,
+		},
+		{
+			name:        "get not matching",
+			method:      http.MethodGet,
+			ifNoneMatch: `"other"`,
+			wantCode:    http.StatusOK,
+			wantETag:    true,
+			wantBody:    true,
+		},
+		{
+			name:     "post",
+			method:   http.MethodPost,
+			wantCode: http.StatusOK,
+			wantBody: true,
+		},
+		{
+			name:        "post matching",
+			method:      http.MethodPost,
+			ifNoneMatch: "*",
+			wantCode:    http.StatusOK,
+			wantBody:    true,
+		},
+		{
+			name:     "post explicit etag",
+			method:   http.MethodPost,
+			opts:     []SendOption{WithETag("v1")},
+			wantCode: http.StatusOK,
+			wantETag: true,
+			wantBody: true,
+		},
+		{
+			name:   "error",
+			method: http.MethodGet,
+			send: func(r *Responder, w http.ResponseWriter, opts ...SendOption) {
+				r.ErrorInputInvalid("bad", w, opts...)
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(WithETags(true))
+
+			send := tt.send
+			if send == nil {
+				send = func(r *Responder, w http.ResponseWriter, opts ...SendOption) {
+					r.DataFound(map[string]string{"name": "a"}, w, opts...)
+				}
+			}
+
+			//Get the ETag to match against.
+			ifNoneMatch := tt.ifNoneMatch
+			if ifNoneMatch == "match" {
+				first := httptest.NewRecorder()
+				send(r, Negotiate(first, httptest.NewRequest(http.MethodGet, "/", nil)), tt.opts...)
+				ifNoneMatch = first.Header().Get("ETag")
+			}
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			send(r, Negotiate(w, req), tt.opts...)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d", w.Code, tt.wantCode)
+			}
+			if got := w.Header().Get("ETag") != ""; got != tt.wantETag {
+				t.Fatalf("got ETag %q, want ETag %v", w.Header().Get("ETag"), tt.wantETag)
+			}
+			if got := w.Body.Len() > 0; got != tt.wantBody {
+				t.Fatalf("got body %q, want body %v", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestETagsEncryptedData(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := New(WithETags(true), WithEncryptedData(func(req *http.Request) (crypto.PublicKey, string, error) {
+		return &key.PublicKey, "", nil
+	}))
+
+	//The ETag must not change between responses with the same Data even though the
+	//encrypted Data does.
+	var etags []string
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r.DataFound("a", Negotiate(w, httptest.NewRequest(http.MethodGet, "/", nil)))
+		etags = append(etags, w.Header().Get("ETag"))
+	}
+
+	if etags[0] == "" || etags[0] != etags[1] {
+		t.Fatalf("got ETags %q", etags)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etags[0])
+	w := httptest.NewRecorder()
+	r.DataFound("a", Negotiate(w, req))
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusNotModified)
+	}
+}
+
+func TestETagsVary(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{
+			name: "single encoder",
+		},
+		{
+			name: "negotiated encoder",
+			opts: []Option{WithEncoders(JSON, XML)},
+			want: []string{"Accept"},
+		},
+		{
+			name: "compression",
+			opts: []Option{WithCompression(1)},
+			want: []string{"Accept-Encoding"},
+		},
+		{
+			name: "negotiated encoder and compression",
+			opts: []Option{WithEncoders(JSON, XML), WithCompression(1)},
+			want: []string{"Accept", "Accept-Encoding"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(append([]Option{WithETags(true)}, tt.opts...)...)
+
+			//The 304 must have the same Vary headers as the 200 it replaces.
+			ok := httptest.NewRecorder()
+			r.DataFound("a", Negotiate(ok, httptest.NewRequest(http.MethodGet, "/", nil)))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("If-None-Match", ok.Header().Get("ETag"))
+			notModified := httptest.NewRecorder()
+			r.DataFound("a", Negotiate(notModified, req))
+
+			if notModified.Code != http.StatusNotModified {
+				t.Fatalf("got status %d, want %d", notModified.Code, http.StatusNotModified)
+			}
+			if got := ok.Header().Values("Vary"); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got 200 Vary %q, want %q", got, tt.want)
+			}
+			if got := notModified.Header().Values("Vary"); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got 304 Vary %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
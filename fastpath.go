@@ -0,0 +1,183 @@
+package output
+
+import (
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+)
+
+// fastPayloadSize is the initial capacity of the buffer used by appendPayload. This
+// fits most ID-only and error responses so the buffer is not grown.
+const fastPayloadSize = 256
+
+// appendPayload appends the JSON encoding of p to dst without using reflection.
+// This only handles the most common shapes of Payloads, those sent by InsertOK,
+// ErrorWithID, Error, and similar funcs where Data is nil or an integer ID and
+// ErrorData only contains strings. False is returned, and dst is returned as is, for
+// any other Payload so that it is encoded by encoding/json.
+//
+// The output must be identical to encoding/json's output for the Payload.
+func appendPayload(dst []byte, p Payload) ([]byte, bool) {
+	if !fastPayload(p) {
+		return dst, false
+	}
+
+	b := append(dst, `{"OK":`...)
+	b = strconv.AppendBool(b, p.OK)
+	b = append(b, `,"Type":`...)
+	b = appendJSONString(b, p.Type)
+
+	switch d := p.Data.(type) {
+	case int64:
+		b = append(b, `,"Data":`...)
+		b = strconv.AppendInt(b, d, 10)
+	case int:
+		b = append(b, `,"Data":`...)
+		b = strconv.AppendInt(b, int64(d), 10)
+	}
+
+	e := p.ErrorData
+	b = append(b, `,"ErrorData":{`...)
+	sep := ""
+	for _, f := range []struct {
+		name, value string
+	}{
+		{"Code", e.Code},
+		{"Error", e.Error},
+		{"Message", e.Message},
+	} {
+		if f.value != "" {
+			b = append(b, sep+`"`+f.name+`":`...)
+			b = appendJSONString(b, f.value)
+			sep = ","
+		}
+	}
+	if e.DocsURL != "" {
+		b = append(b, sep+`"DocsURL":`...)
+		b = appendJSONString(b, e.DocsURL)
+		sep = ","
+	}
+	if e.Retryable {
+		b = append(b, sep+`"Retryable":true`...)
+		sep = ","
+	}
+	if e.RetryAfterSeconds != 0 {
+		b = append(b, sep+`"RetryAfterSeconds":`...)
+		b = strconv.AppendInt(b, int64(e.RetryAfterSeconds), 10)
+	}
+	b = append(b, '}')
+
+	for _, f := range []struct {
+		name, value string
+	}{
+		{"RequestID", p.RequestID},
+		{"TraceID", p.TraceID},
+		{"SpanID", p.SpanID},
+	} {
+		if f.value != "" {
+			b = append(b, `,"`+f.name+`":`...)
+			b = appendJSONString(b, f.value)
+		}
+	}
+	if p.APIVersion != "" {
+		b = append(b, `,"APIVersion":`...)
+		b = appendJSONString(b, p.APIVersion)
+	}
+	if p.Sequence != 0 {
+		b = append(b, `,"Sequence":`...)
+		b = strconv.AppendUint(b, p.Sequence, 10)
+	}
+	if p.Datetime != "" {
+		b = append(b, `,"Datetime":`...)
+		b = appendJSONString(b, p.Datetime)
+	}
+
+	b = append(b, '}')
+	return b, true
+}
+
+// fastPayload reports if p can be encoded by appendPayload.
+func fastPayload(p Payload) bool {
+	switch p.Data.(type) {
+	case nil, int64, int:
+	default:
+		return false
+	}
+
+	e := p.ErrorData
+	return len(e.Fields) == 0 &&
+		len(e.Details) == 0 &&
+		len(e.Chain) == 0 &&
+		len(e.Stack) == 0 &&
+		len(p.Errors) == 0 &&
+		p.Links == nil &&
+		len(p.Warnings) == 0 &&
+		len(p.Meta) == 0 &&
+		p.DurationMS == 0 &&
+		p.keys == nil
+}
+
+// jsonReplacement is how encoding/json writes invalid UTF-8, either the escaped or
+// the literal replacement character depending on the version of Go.
+var jsonReplacement = func() string {
+	b, err := json.Marshal("\xff")
+	if err != nil || len(b) < 2 {
+		return `\ufffd`
+	}
+
+	return string(b[1 : len(b)-1])
+}()
+
+// appendJSONString appends s to dst as a JSON string, escaped as encoding/json
+// does, including the escaping of HTML characters.
+func appendJSONString(dst []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, jsonReplacement...)
+		case r == '\u2028' || r == '\u2029':
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xF])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+
+	dst = append(dst, s[start:]...)
+	dst = append(dst, '"')
+	return dst
+}
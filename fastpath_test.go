@@ -0,0 +1,197 @@
+package output
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestAppendPayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        Payload
+		wantFast bool
+	}{
+		{
+			name:     "empty",
+			p:        Payload{},
+			wantFast: true,
+		},
+		{
+			name:     "insert ok",
+			p:        Payload{OK: true, Type: "insertOK", Data: int64(42), Datetime: "2024-01-02T03:04:05.000Z"},
+			wantFast: true,
+		},
+		{
+			name:     "int data",
+			p:        Payload{OK: true, Type: "insertOK", Data: -7},
+			wantFast: true,
+		},
+		{
+			name: "every fast field",
+			p: Payload{
+				OK:   false,
+				Type: "error",
+				Data: int64(1),
+				ErrorData: ErrorPayload{
+					Code:              "notFound",
+					Error:             "not found",
+					Message:           "The item was not found.",
+					DocsURL:           "https://example.com/errors#notFound",
+					Retryable:         true,
+					RetryAfterSeconds: 30,
+				},
+				RequestID:  "req1",
+				TraceID:    "trace1",
+				SpanID:     "span1",
+				APIVersion: "v1",
+				Sequence:   99,
+				Datetime:   "2024-01-02T03:04:05.000Z",
+			},
+			wantFast: true,
+		},
+		{
+			name:     "only retry after",
+			p:        Payload{ErrorData: ErrorPayload{RetryAfterSeconds: 5}},
+			wantFast: true,
+		},
+		{
+			name:     "html escaping",
+			p:        Payload{Type: "<script>&</script>", ErrorData: ErrorPayload{Message: `a "quoted" \ value`}},
+			wantFast: true,
+		},
+		{
+			name:     "control characters",
+			p:        Payload{Type: "a\nb\rc\td\x00e\x1f"},
+			wantFast: true,
+		},
+		{
+			name:     "invalid utf-8",
+			p:        Payload{Type: "a\xffb\xc3", ErrorData: ErrorPayload{Error: "\xed\xa0\x80"}},
+			wantFast: true,
+		},
+		{
+			name:     "line and paragraph separators",
+			p:        Payload{Type: "a\u2028b\u2029c"},
+			wantFast: true,
+		},
+		{
+			name:     "multibyte",
+			p:        Payload{Type: "héllo, 世界 🌍"},
+			wantFast: true,
+		},
+		{
+			name:     "empty meta",
+			p:        Payload{Type: "a", Meta: map[string]interface{}{}},
+			wantFast: true,
+		},
+		{
+			name:     "empty errors and warnings",
+			p:        Payload{Type: "a", Errors: []ErrorPayload{}, Warnings: []Warning{}},
+			wantFast: true,
+		},
+		{
+			name: "string data",
+			p:    Payload{Type: "a", Data: "b"},
+		},
+		{
+			name: "empty slice data",
+			p:    Payload{Type: "a", Data: []int{}},
+		},
+		{
+			name: "nil slice data",
+			p:    Payload{Type: "a", Data: []int(nil)},
+		},
+		{
+			name: "nil pointer data",
+			p:    Payload{Type: "a", Data: (*int)(nil)},
+		},
+		{
+			name: "meta",
+			p:    Payload{Type: "a", Meta: map[string]interface{}{"b": 1}},
+		},
+		{
+			name: "duration",
+			p:    Payload{Type: "a", DurationMS: 1.5},
+		},
+		{
+			name: "fields",
+			p:    Payload{Type: "a", ErrorData: ErrorPayload{Fields: []FieldError{{Field: "b"}}}},
+		},
+		{
+			name: "links",
+			p:    Payload{Type: "a", Links: &Links{Self: "/a"}},
+		},
+		{
+			name: "keys",
+			p:    Payload{Type: "a", keys: &envelopeKeys{keyCase: KeyCaseSnake}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fast := appendPayload(nil, tt.p)
+			if fast != tt.wantFast {
+				t.Fatalf("got fast %v, want %v", fast, tt.wantFast)
+			}
+			if !fast {
+				if len(got) != 0 {
+					t.Fatalf("got %s, want nothing appended", got)
+				}
+				return
+			}
+
+			want, err := json.Marshal(payloadJSON(tt.p))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Fatalf("got  %s\nwant %s", got, want)
+			}
+		})
+	}
+}
+
+func TestPayloadMarshalJSONNonFinite(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Payload
+	}{
+		{"NaN duration", Payload{DurationMS: math.NaN()}},
+		{"Inf duration", Payload{DurationMS: math.Inf(1)}},
+		{"NaN data", Payload{Data: math.NaN()}},
+		{"-Inf data", Payload{Data: math.Inf(-1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := json.Marshal(tt.p); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func BenchmarkPayloadMarshalJSON(b *testing.B) {
+	p := Payload{
+		OK:        false,
+		Type:      "error",
+		ErrorData: ErrorPayload{Code: "notFound", Error: "not found", Message: "The item was not found."},
+		RequestID: "req1",
+		Datetime:  "2024-01-02T03:04:05.000Z",
+	}
+
+	b.Run("fast", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			appendPayload(make([]byte, 0, fastPayloadSize), p)
+		}
+	})
+
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			json.Marshal(payloadJSON(p))
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// genericMap is a JSON object with its members kept in order.
+type genericMap []genericMember
+
+// genericMember is a member of a JSON object.
+type genericMember struct {
+	Key   string
+	Value interface{}
+}
+
+// MarshalJSON implements json.Marshaler, encoding the members in order.
+func (m genericMap) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+
+	for i, member := range m {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		k, err := json.Marshal(member.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := json.Marshal(member.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// toGeneric converts v to a tree of generic values: nil, bool, string, json.Number,
+// []interface{}, and genericMap. This is done by encoding v as JSON and decoding the
+// result. Doing so means that non-JSON Encoders use the same field names, and honor
+// the same struct tags and json.Marshaler implementations, as the JSON Encoder.
+func toGeneric(v interface{}) (g interface{}, err error) {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.UseNumber()
+
+	g, err = decodeGeneric(dec)
+	return
+}
+
+// decodeGeneric decodes the next JSON value from dec.
+func decodeGeneric(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			m := genericMap{}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				key, ok := keyTok.(string)
+				if !ok {
+					return nil, errors.New("output: invalid JSON object key")
+				}
+
+				value, err := decodeGeneric(dec)
+				if err != nil {
+					return nil, err
+				}
+
+				m = append(m, genericMember{Key: key, Value: value})
+			}
+
+			//Consume the closing delimiter.
+			_, err = dec.Token()
+			return m, err
+
+		case '[':
+			a := []interface{}{}
+			for dec.More() {
+				value, err := decodeGeneric(dec)
+				if err != nil {
+					return nil, err
+				}
+
+				a = append(a, value)
+			}
+
+			//Consume the closing delimiter.
+			_, err = dec.Token()
+			return a, err
+
+		default:
+			return nil, errors.New("output: unexpected JSON delimiter")
+		}
+
+	default:
+		//nil, bool, string, or json.Number.
+		return tok, nil
+	}
+}
+
+// genericEncoder is an Encoder that encodes the generic form of a value, see
+// toGeneric, using an encode func.
+type genericEncoder struct {
+	contentType string
+	encode      func(w io.Writer, g interface{}) error
+}
+
+// ContentType implements Encoder.
+func (ge genericEncoder) ContentType() string {
+	return ge.contentType
+}
+
+// Encode implements Encoder.
+func (ge genericEncoder) Encode(w io.Writer, v interface{}) (err error) {
+	g, err := toGeneric(v)
+	if err != nil {
+		return
+	}
+
+	err = ge.encode(w, g)
+	return
+}
@@ -1,3 +1,24 @@
 module github.com/c9845/output
 
 go 1.22
+
+require (
+	github.com/andybalholm/brotli v1.1.1
+	github.com/gorilla/websocket v1.5.3
+	github.com/klauspost/compress v1.17.11
+	github.com/rabbitmq/amqp091-go v1.10.0
+	github.com/segmentio/kafka-go v0.4.47
+	github.com/valyala/fasthttp v1.55.0
+	go.opentelemetry.io/otel v1.31.0
+	go.opentelemetry.io/otel/trace v1.31.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240604185151-ef581f913117
+	google.golang.org/grpc v1.66.2
+	google.golang.org/protobuf v1.36.6
+	nhooyr.io/websocket v1.8.17
+)
+
+require (
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
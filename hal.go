@@ -0,0 +1,134 @@
+package output
+
+import "sort"
+
+// HALEmbeddedRel is the relation Data is embedded under when responses are sent as
+// HAL documents, see WithHAL.
+const HALEmbeddedRel = "data"
+
+// halContentType is the content type of HAL documents.
+const halContentType = "application/hal+json"
+
+// WithHAL causes responses to be sent as HAL documents, with the
+// application/hal+json content type, instead of as a Payload. This only applies when
+// the response is encoded with the JSON Encoder. This allows integration with HAL
+// based tooling, such as the HAL browser.
+//
+// The Payload is mapped as follows:
+//   - Data is embedded in _embedded under the HALEmbeddedRel relation.
+//   - Links are sent in _links. Self is the "self" relation, each of Related uses
+//     its relation name, and each of Actions uses its Name.
+//   - Other fields of the Payload, such as Type, ErrorData, and Meta, are sent as
+//     properties of the document with lower camel case names, or the casing set
+//     with WithKeyCase. Names set with WithFieldNames and WithDatetimeField are
+//     used as is.
+func WithHAL(b bool) Option {
+	return func(r *Responder) {
+		r.hal = b
+	}
+}
+
+// halLink is a HAL link object.
+type halLink struct {
+	Href string `json:"href"`
+
+	//Method is not defined by HAL but is used to describe actions, see
+	//LinkAction.
+	Method string `json:"method,omitempty"`
+}
+
+// newHALDocument builds a HAL document from a Payload. A genericMap is used so that
+// properties are encoded in a consistent order.
+func newHALDocument(p *Payload) (doc genericMap) {
+	add := func(key string, value interface{}) {
+		doc = append(doc, genericMember{Key: key, Value: value})
+	}
+
+	if p.Links != nil {
+		links := genericMap{}
+		if p.Links.Self != "" {
+			links = append(links, genericMember{Key: "self", Value: halLink{Href: p.Links.Self}})
+		}
+
+		//Relations are sorted so the document is encoded consistently.
+		rels := make([]string, 0, len(p.Links.Related))
+		for rel := range p.Links.Related {
+			rels = append(rels, rel)
+		}
+		sort.Strings(rels)
+
+		for _, rel := range rels {
+			links = append(links, genericMember{Key: rel, Value: halLink{Href: p.Links.Related[rel]}})
+		}
+		for _, a := range p.Links.Actions {
+			links = append(links, genericMember{Key: a.Name, Value: halLink{Href: a.Href, Method: a.Method}})
+		}
+
+		add("_links", links)
+	}
+
+	if p.Data != nil {
+		add("_embedded", genericMap{{Key: HALEmbeddedRel, Value: p.Data}})
+	}
+
+	//Properties use lower camel case unless a different casing was configured. The
+	//keys within ErrorData, Errors, and Warnings use the same casing. Names set with
+	//WithFieldNames, or WithDatetimeField, take precedence over the casing.
+	ek := &envelopeKeys{keyCase: KeyCaseCamel}
+	if p.keys != nil {
+		ek.names = p.keys.names
+		if p.keys.keyCase != KeyCaseDefault {
+			ek.keyCase = p.keys.keyCase
+		}
+	}
+	prop := func(field string, value interface{}) {
+		add(ek.key(field, field), value)
+	}
+	recased := func(v interface{}) interface{} {
+		g, err := toGeneric(v)
+		if err != nil {
+			return v
+		}
+		recase(g, ek.keyCase)
+		return g
+	}
+
+	prop("OK", p.OK)
+	prop("Type", p.Type)
+
+	if !p.ErrorData.IsZero() {
+		prop("ErrorData", recased(p.ErrorData))
+	}
+	if len(p.Errors) > 0 {
+		prop("Errors", recased(p.Errors))
+	}
+	if len(p.Warnings) > 0 {
+		prop("Warnings", recased(p.Warnings))
+	}
+	if len(p.Meta) > 0 {
+		prop("Meta", p.Meta)
+	}
+	if p.RequestID != "" {
+		prop("RequestID", p.RequestID)
+	}
+	if p.TraceID != "" {
+		prop("TraceID", p.TraceID)
+	}
+	if p.SpanID != "" {
+		prop("SpanID", p.SpanID)
+	}
+	if p.DurationMS != 0 {
+		prop("DurationMS", p.DurationMS)
+	}
+	if p.APIVersion != "" {
+		prop("APIVersion", p.APIVersion)
+	}
+	if p.Sequence > 0 {
+		prop("Sequence", p.Sequence)
+	}
+	if p.Datetime != "" {
+		prop("Datetime", p.Datetime)
+	}
+
+	return
+}
@@ -0,0 +1,89 @@
+package output
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHALDocument(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		send func(r *Responder, w *httptest.ResponseRecorder)
+		want string
+	}{
+		{
+			name: "data",
+			send: func(r *Responder, w *httptest.ResponseRecorder) {
+				r.DataFound(1, w, WithLinks(&Links{Self: "/a"}))
+			},
+			want: `{"_links":{"self":{"href":"/a"}},"_embedded":{"data":1},"ok":true,"type":"dataFound"}`,
+		},
+		{
+			name: "envelope fields",
+			opts: []Option{WithAPIVersion("v2"), WithSequence(true)},
+			send: func(r *Responder, w *httptest.ResponseRecorder) {
+				p := Payload{OK: true, Type: "dataFound", TraceID: "t1", SpanID: "s1", DurationMS: 1.5}
+				r.Send(p, w, 200)
+			},
+			want: `{"ok":true,"type":"dataFound","traceID":"t1","spanID":"s1","durationMS":1.5,"apiVersion":"v2","sequence":1}`,
+		},
+		{
+			name: "error default case",
+			send: func(r *Responder, w *httptest.ResponseRecorder) {
+				r.Error(errors.New("bad"), "Bad.", w)
+			},
+			want: `{"ok":false,"type":"error","errorData":{"error":"bad","message":"Bad."}}`,
+		},
+		{
+			name: "error with fields and warnings",
+			send: func(r *Responder, w *httptest.ResponseRecorder) {
+				p := Payload{
+					Type:      "error",
+					ErrorData: ErrorPayload{Error: "bad", Fields: []FieldError{{Field: "name", Rule: "required"}}},
+					Warnings:  []Warning{{Code: "a", Message: "A."}},
+				}
+				r.Send(p, w, 400)
+			},
+			want: `{"ok":false,"type":"error","errorData":{"error":"bad","fields":[{"field":"name","rule":"required"}]},"warnings":[{"code":"a","message":"A."}]}`,
+		},
+		{
+			name: "renamed datetime",
+			opts: []Option{WithOmitDatetime(false), WithDatetimeField("timestamp")},
+			send: func(r *Responder, w *httptest.ResponseRecorder) {
+				p := Payload{OK: true, Type: "dataFound", Datetime: "2024-01-02T03:04:05.000Z"}
+				r.Send(p, w, 200)
+			},
+			want: `{"ok":true,"type":"dataFound","timestamp":"2024-01-02T03:04:05.000Z"}`,
+		},
+		{
+			name: "field names",
+			opts: []Option{WithFieldNames(map[string]string{"OK": "success", "ErrorData": "error"})},
+			send: func(r *Responder, w *httptest.ResponseRecorder) {
+				r.Error(errors.New("bad"), "Bad.", w)
+			},
+			want: `{"success":false,"type":"error","error":{"error":"bad","message":"Bad."}}`,
+		},
+		{
+			name: "error snake case",
+			opts: []Option{WithKeyCase(KeyCaseSnake)},
+			send: func(r *Responder, w *httptest.ResponseRecorder) {
+				r.Error(errors.New("bad"), "Bad.", w, WithRetryAfter(2e9))
+			},
+			want: `{"ok":false,"type":"error","error_data":{"error":"bad","message":"Bad.","retryable":true,"retry_after_seconds":2}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(append([]Option{WithHAL(true), WithOmitDatetime(true)}, tt.opts...)...)
+			w := httptest.NewRecorder()
+			tt.send(r, w)
+
+			if got := w.Body.String(); got != tt.want+"\n" {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package output
+
+import "net/http"
+
+// HandlerFunc is a handler that returns the response to send instead of sending it.
+// This allows handlers to be pure funcs that are easily tested by checking the
+// returned Payload and error.
+//
+// When a non-nil error is returned, the Payload is ignored and the error is sent as
+// if by Error, so the HTTP status code and message type are chosen per MapError or
+// the error's StatusCoder and MessageTyper implementations. Otherwise, the Payload is
+// sent as if by Send with the following defaults:
+//   - OK is true unless ErrorData or Errors is provided.
+//   - The HTTP status code is the Responder's success code, or error code if OK is
+//     false.
+//   - Type is TypeDataFound, or TypeError if OK is false, if no Type was provided.
+//
+// HandlerFunc implements http.Handler using the default Responder. Use
+// Responder.Handle to use a different Responder.
+//
+//	http.Handle("/users", output.HandlerFunc(func(r *http.Request) (output.Payload, error) {
+//		users, err := getUsers(r.Context())
+//		if err != nil {
+//			return output.Payload{}, err
+//		}
+//
+//		return output.Payload{Data: users}, nil
+//	}))
+type HandlerFunc func(req *http.Request) (Payload, error)
+
+// ServeHTTP implements http.Handler.
+func (h HandlerFunc) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	std.Handle(h).ServeHTTP(w, req)
+}
+
+// Handle returns an http.Handler that calls h and sends the returned Payload or
+// error using the default Responder. See HandlerFunc.
+func Handle(h HandlerFunc) http.Handler {
+	return std.Handle(h)
+}
+
+// Handle returns an http.Handler that calls h and sends the returned Payload or
+// error using the Responder. See HandlerFunc.
+func (r *Responder) Handle(h HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w = Negotiate(w, req)
+
+		p, err := h(req)
+		if err != nil {
+			r.Error(err, "", w)
+			return
+		}
+
+		p.OK = p.ErrorData.IsZero() && len(p.Errors) == 0
+
+		code, msgType := r.successCode, TypeDataFound
+		if !p.OK {
+			code, msgType = r.errorCode, TypeError
+		}
+
+		if p.Type == "" {
+			p.Type = string(msgType)
+		}
+
+		err = r.Send(p, w, code)
+		if err != nil {
+			r.log(w, "output.Handle: could not send response", "error", err)
+		}
+	})
+}
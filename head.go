@@ -0,0 +1,14 @@
+package output
+
+import "net/http"
+
+// isHead reports if the response being sent to w is for a HEAD request. The
+// ResponseWriter must carry the request, see Negotiate.
+//
+// Responses to HEAD requests have the same headers, including Content-Length and
+// ETag, as responses to GET requests but the body is not sent. This allows clients
+// to check if a resource exists, or has changed, without downloading it.
+func isHead(w http.ResponseWriter) bool {
+	req := requestFrom(w)
+	return req != nil && req.Method == http.MethodHead
+}
@@ -0,0 +1,39 @@
+package output
+
+import "net/http"
+
+// HeaderAPIVersion is the header used to send the API version, see WithAPIVersion.
+const HeaderAPIVersion = "X-API-Version"
+
+// WithAPIVersion sets the version of your API that is included as the Payload's
+// APIVersion, and in the X-API-Version header, of every response.
+func WithAPIVersion(version string) Option {
+	return func(r *Responder) {
+		r.apiVersion = version
+	}
+}
+
+// WithDefaultHeader sets a header on every response. Headers set with WithHeader,
+// for a single response, override default headers.
+func WithDefaultHeader(key, value string) Option {
+	return func(r *Responder) {
+		if r.defaultHeader == nil {
+			r.defaultHeader = http.Header{}
+		}
+
+		r.defaultHeader.Set(key, value)
+	}
+}
+
+// setDefaultHeaders sets the API version and default headers on the response and
+// Payload.
+func (r *Responder) setDefaultHeaders(p *Payload, h http.Header) {
+	if r.apiVersion != "" {
+		p.APIVersion = r.apiVersion
+		h.Set(HeaderAPIVersion, r.apiVersion)
+	}
+
+	for k, v := range r.defaultHeader {
+		h[k] = v
+	}
+}
@@ -0,0 +1,125 @@
+package output
+
+import (
+	"net/http"
+	"time"
+)
+
+// BeforeSendFunc is called before each response is encoded and written. The Payload
+// can be modified, for example to add Meta. The request is nil unless the
+// ResponseWriter carries the request, see Negotiate. p is reused once the response
+// is sent so it must not be retained.
+type BeforeSendFunc func(p *Payload, req *http.Request)
+
+// AfterSendFunc is called after each response is written.
+type AfterSendFunc func(res SendResult)
+
+// SendResult describes a response that was sent, see OnAfterSend.
+type SendResult struct {
+	//Payload is the Payload that was sent.
+	Payload Payload
+
+	//Request is the request that was responded to. This is nil unless the
+	//ResponseWriter carried the request, see Negotiate.
+	Request *http.Request
+
+	//ResponseCode is the HTTP status code that was sent.
+	ResponseCode int
+
+	//Duration is how long it took to handle the request, from the start time
+	//recorded by TimingMiddleware, or how long it took to encode and write the
+	//response if TimingMiddleware is not used.
+	Duration time.Duration
+
+	//Size is the number of bytes of the response body that were written. This is
+	//zero if Data was streamed from a reader, see DataFoundReader.
+	Size int
+
+	//Err is the error, if any, that occured while sending the response.
+	Err error
+}
+
+// OnBeforeSend registers a func called before each response is sent by the default
+// Responder. See Responder.OnBeforeSend.
+func OnBeforeSend(fn BeforeSendFunc) {
+	std.OnBeforeSend(fn)
+}
+
+// OnAfterSend registers a func called after each response is sent by the default
+// Responder. See Responder.OnAfterSend.
+func OnAfterSend(fn AfterSendFunc) {
+	std.OnAfterSend(fn)
+}
+
+// WithBeforeSend registers a func called before each response is sent when creating
+// a Responder. See Responder.OnBeforeSend.
+func WithBeforeSend(fn BeforeSendFunc) Option {
+	return func(r *Responder) {
+		r.OnBeforeSend(fn)
+	}
+}
+
+// WithAfterSend registers a func called after each response is sent when creating a
+// Responder. See Responder.OnAfterSend.
+func WithAfterSend(fn AfterSendFunc) Option {
+	return func(r *Responder) {
+		r.OnAfterSend(fn)
+	}
+}
+
+// OnBeforeSend registers a func called before each response is sent. This allows
+// every response to be modified in one place, for example to inject Meta, instead of
+// wrapping each func. Funcs are called in the order they were registered, after
+// SendOptions have been applied.
+func (r *Responder) OnBeforeSend(fn BeforeSendFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.beforeSend = append(r.beforeSend, fn)
+}
+
+// OnAfterSend registers a func called after each response is sent. This allows
+// every response to be audited, or measured, in one place. Funcs are called in the
+// order they were registered.
+func (r *Responder) OnAfterSend(fn AfterSendFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.afterSend = append(r.afterSend, fn)
+}
+
+// runBeforeSend calls each BeforeSendFunc.
+func (r *Responder) runBeforeSend(p *Payload, w http.ResponseWriter) {
+	r.mu.RLock()
+	hooks := r.beforeSend
+	r.mu.RUnlock()
+
+	if len(hooks) == 0 {
+		return
+	}
+
+	req := requestFrom(w)
+	for _, fn := range hooks {
+		fn(p, req)
+	}
+}
+
+// runAfterSend logs the response, records metrics, samples the response, and calls
+// each AfterSendFunc.
+func (r *Responder) runAfterSend(w http.ResponseWriter, res SendResult) {
+	r.log(w, "output.send", "type", res.Payload.Type, "status", res.ResponseCode, "error", res.Err, "duration", res.Duration)
+
+	if r.metrics != nil {
+		r.metrics.ObserveResponse(res.Payload.Type, res.ResponseCode, res.Duration)
+	}
+
+	r.sample(res)
+
+	r.mu.RLock()
+	hooks := r.afterSend
+	r.mu.RUnlock()
+
+	for _, fn := range hooks {
+		fn(res)
+	}
+}
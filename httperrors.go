@@ -0,0 +1,185 @@
+package output
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Define errors returned in HTTP responses by the status-specific error funcs.
+var (
+	errBadRequest    = errors.New("bad request")
+	errUnauthorized  = errors.New("unauthorized")
+	errForbidden     = errors.New("forbidden")
+	errNotFound      = errors.New("not found")
+	errConflict      = errors.New("conflict")
+	errUnprocessable = errors.New("unprocessable")
+
+	errMethodNotAllowed   = errors.New("method not allowed")
+	errServiceUnavailable = errors.New("service unavailable")
+)
+
+// The funcs below are used for common client errors. They send the same Payload as
+// Error but with an applicable 4xx HTTP status code instead of the Responder's
+// error status code.
+
+// ErrorBadRequest is used when a request is malformed and cannot be handled. This
+// returns an HTTP status 400.
+func ErrorBadRequest(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorBadRequest(msg, w, opts...)
+	return
+}
+
+// ErrorUnauthorized is used when a request requires authentication that was not
+// provided or is invalid. This returns an HTTP status 401.
+func ErrorUnauthorized(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorUnauthorized(msg, w, opts...)
+	return
+}
+
+// ErrorForbidden is used when the authenticated client is not allowed to perform
+// the request. This returns an HTTP status 403.
+func ErrorForbidden(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorForbidden(msg, w, opts...)
+	return
+}
+
+// ErrorNotFound is used when the requested data does not exist. This returns an
+// HTTP status 404.
+func ErrorNotFound(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorNotFound(msg, w, opts...)
+	return
+}
+
+// ErrorConflict is used when a request conflicts with the current state of the
+// data, for example an edit based on outdated data. This returns an HTTP status 409.
+func ErrorConflict(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorConflict(msg, w, opts...)
+	return
+}
+
+// ErrorUnprocessable is used when a request is well-formed but cannot be handled
+// due to semantic errors. This returns an HTTP status 422.
+func ErrorUnprocessable(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorUnprocessable(msg, w, opts...)
+	return
+}
+
+// ErrorMethodNotAllowed is used when the request's HTTP method is not supported by
+// the requested endpoint, typically in a router's fallback handler. This returns an
+// HTTP status 405, sets the Allow header to the allowed methods, and uses the
+// TypeMethodNotAllowed message type.
+func ErrorMethodNotAllowed(allowed []string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorMethodNotAllowed(allowed, w, opts...)
+	return
+}
+
+// ErrorServiceUnavailable is used when a request cannot be handled right now, for
+// example during a deploy. This returns an HTTP status 503. Use WithRetryAfter to
+// tell the client when to retry. See Maintenance to send this for all requests.
+func ErrorServiceUnavailable(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorServiceUnavailable(msg, w, opts...)
+	return
+}
+
+// ErrorBadRequest sends a 400 error. See the package-level ErrorBadRequest.
+func (r *Responder) ErrorBadRequest(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.sendError(TypeError, errBadRequest, msg, nil, w, http.StatusBadRequest, opts...)
+	return
+}
+
+// ErrorUnauthorized sends a 401 error. See the package-level ErrorUnauthorized.
+func (r *Responder) ErrorUnauthorized(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.sendError(TypeError, errUnauthorized, msg, nil, w, http.StatusUnauthorized, opts...)
+	return
+}
+
+// ErrorForbidden sends a 403 error. See the package-level ErrorForbidden.
+func (r *Responder) ErrorForbidden(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.sendError(TypeError, errForbidden, msg, nil, w, http.StatusForbidden, opts...)
+	return
+}
+
+// ErrorNotFound sends a 404 error. See the package-level ErrorNotFound.
+func (r *Responder) ErrorNotFound(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.sendError(TypeError, errNotFound, msg, nil, w, http.StatusNotFound, opts...)
+	return
+}
+
+// ErrorConflict sends a 409 error. See the package-level ErrorConflict.
+func (r *Responder) ErrorConflict(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.sendError(TypeError, errConflict, msg, nil, w, http.StatusConflict, opts...)
+	return
+}
+
+// ErrorUnprocessable sends a 422 error. See the package-level ErrorUnprocessable.
+func (r *Responder) ErrorUnprocessable(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.sendError(TypeError, errUnprocessable, msg, nil, w, http.StatusUnprocessableEntity, opts...)
+	return
+}
+
+// ErrorMethodNotAllowed sends a 405 error. See the package-level
+// ErrorMethodNotAllowed.
+func (r *Responder) ErrorMethodNotAllowed(allowed []string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	methods := strings.Join(allowed, ", ")
+	opts = append([]SendOption{WithHeader("Allow", methods)}, opts...)
+
+	msg := "This endpoint does not support the requested HTTP method."
+	if methods != "" {
+		msg = "This endpoint only supports the following HTTP methods: " + methods + "."
+	}
+
+	err = r.sendError(TypeMethodNotAllowed, errMethodNotAllowed, msg, nil, w, http.StatusMethodNotAllowed, opts...)
+	return
+}
+
+// ErrorServiceUnavailable sends a 503 error. See the package-level
+// ErrorServiceUnavailable.
+func (r *Responder) ErrorServiceUnavailable(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.sendError(TypeError, errServiceUnavailable, msg, nil, w, http.StatusServiceUnavailable, opts...)
+	return
+}
+
+// ErrorWithCode is similar to Error but allows for returning any HTTP status code.
+// This is used when one of the status-specific error funcs doesn't fit, for example
+// when returning a 429, and you don't want to construct a Payload to use with Send.
+func ErrorWithCode(errType error, errMsg string, responseCode int, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorWithCode(errType, errMsg, responseCode, w, opts...)
+	return
+}
+
+// ErrorWithCodeAndID is similar to ErrorWithCode but allows for returning an ID,
+// like ErrorWithID.
+func ErrorWithCodeAndID(errType error, errMsg string, responseCode int, id int64, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorWithCodeAndID(errType, errMsg, responseCode, id, w, opts...)
+	return
+}
+
+// ErrorWithCode sends an error with any HTTP status code. See the package-level
+// ErrorWithCode.
+func (r *Responder) ErrorWithCode(errType error, errMsg string, responseCode int, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.errorWithCode(errType, errMsg, responseCode, nil, w, opts...)
+	return
+}
+
+// ErrorWithCodeAndID sends an error, with an ID, with any HTTP status code. See the
+// package-level ErrorWithCodeAndID.
+func (r *Responder) ErrorWithCodeAndID(errType error, errMsg string, responseCode int, id int64, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.errorWithCode(errType, errMsg, responseCode, id, w, opts...)
+	return
+}
+
+// errorWithCode sends an error using the provided HTTP status code instead of the
+// mapped status code. The message type is still chosen per mapError.
+func (r *Responder) errorWithCode(errType error, errMsg string, responseCode int, data interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	if responseCode < http.StatusContinue {
+		r.log(w, "output.ErrorWithCode: invalid HTTP response code provided", "status", responseCode)
+
+		err = ErrInvalidResponseCode
+		return
+	}
+
+	_, msgType := r.mapError(errType)
+	err = r.sendError(msgType, errType, errMsg, data, w, responseCode, opts...)
+	return
+}
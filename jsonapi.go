@@ -0,0 +1,268 @@
+package output
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// JSONAPIResource is implemented by Data to provide the type and id of the
+// resource when responses are sent as JSON:API documents, see WithJSONAPI.
+type JSONAPIResource interface {
+	JSONAPIType() string
+	JSONAPIID() string
+}
+
+// JSONAPIResourceFunc returns the type and id of v when responses are sent as
+// JSON:API documents. False is returned if v is not a resource. This is used for
+// Data that does not implement JSONAPIResource, see WithJSONAPIResourceFunc.
+type JSONAPIResourceFunc func(v interface{}) (typ, id string, ok bool)
+
+// WithJSONAPI causes responses to be sent as JSON:API documents, with the
+// application/vnd.api+json content type, instead of as a Payload. This only applies
+// when the response is encoded with the JSON Encoder. This allows teams required to
+// follow JSON:API to still use this package's funcs.
+//
+// The Payload is mapped as follows:
+//   - Data is sent as a resource object, or an array of resource objects, if the
+//     type and id can be determined (see JSONAPIResource and
+//     WithJSONAPIResourceFunc). The Data is used as the resource's attributes.
+//     Otherwise, Data is sent in the top-level meta.
+//   - ErrorData, or each of Errors, is sent as an error object. Each FieldError is
+//     sent as its own error object with a source pointer to the field.
+//   - Meta, and fields of the Payload that JSON:API has no place for, such as Type
+//     and Datetime, are sent in the top-level meta.
+//   - Links are sent as top-level links.
+func WithJSONAPI(b bool) Option {
+	return func(r *Responder) {
+		r.jsonAPI = b
+	}
+}
+
+// WithJSONAPIResourceFunc sets the func used to determine the type and id of Data
+// that does not implement JSONAPIResource. See WithJSONAPI.
+func WithJSONAPIResourceFunc(fn JSONAPIResourceFunc) Option {
+	return func(r *Responder) {
+		r.jsonAPIResource = fn
+	}
+}
+
+// jsonAPIDocument is a JSON:API top-level document.
+type jsonAPIDocument struct {
+	Data    interface{}            `json:"data,omitempty"`
+	Errors  []jsonAPIError         `json:"errors,omitempty"`
+	Meta    map[string]interface{} `json:"meta,omitempty"`
+	Links   map[string]string      `json:"links,omitempty"`
+	JSONAPI jsonAPIObject          `json:"jsonapi"`
+}
+
+// jsonAPIObject describes the JSON:API version used.
+type jsonAPIObject struct {
+	Version string `json:"version"`
+}
+
+// jsonAPIResource is a JSON:API resource object.
+type jsonAPIResource struct {
+	Type       string      `json:"type"`
+	ID         string      `json:"id"`
+	Attributes interface{} `json:"attributes,omitempty"`
+}
+
+// jsonAPIError is a JSON:API error object.
+type jsonAPIError struct {
+	Status string                 `json:"status,omitempty"`
+	Code   string                 `json:"code,omitempty"`
+	Title  string                 `json:"title,omitempty"`
+	Detail string                 `json:"detail,omitempty"`
+	Source *jsonAPIErrorSource    `json:"source,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// jsonAPIErrorSource identifies the part of the request that caused an error.
+type jsonAPIErrorSource struct {
+	Pointer string `json:"pointer,omitempty"`
+}
+
+// newJSONAPIDocument builds a JSON:API document from a Payload.
+func (r *Responder) newJSONAPIDocument(p *Payload, responseCode int) (doc jsonAPIDocument, err error) {
+	doc = jsonAPIDocument{
+		JSONAPI: jsonAPIObject{Version: "1.1"},
+		Meta:    map[string]interface{}{},
+	}
+
+	for k, v := range p.Meta {
+		doc.Meta[k] = v
+	}
+	doc.Meta["messageType"] = p.Type
+	if p.Datetime != "" {
+		doc.Meta["datetime"] = p.Datetime
+	}
+	if p.RequestID != "" {
+		doc.Meta["requestID"] = p.RequestID
+	}
+	if p.TraceID != "" {
+		doc.Meta["traceID"] = p.TraceID
+	}
+	if p.SpanID != "" {
+		doc.Meta["spanID"] = p.SpanID
+	}
+	if p.DurationMS != 0 {
+		doc.Meta["durationMS"] = p.DurationMS
+	}
+	if p.APIVersion != "" {
+		doc.Meta["apiVersion"] = p.APIVersion
+	}
+	if p.Sequence > 0 {
+		doc.Meta["sequence"] = p.Sequence
+	}
+	if len(p.Warnings) > 0 {
+		doc.Meta["warnings"] = p.Warnings
+	}
+
+	if p.Links != nil {
+		doc.Links = map[string]string{}
+		if p.Links.Self != "" {
+			doc.Links["self"] = p.Links.Self
+		}
+		for rel, href := range p.Links.Related {
+			doc.Links[rel] = href
+		}
+		if len(p.Links.Actions) > 0 {
+			doc.Meta["actions"] = p.Links.Actions
+		}
+	}
+
+	if p.OK {
+		if p.Data != nil {
+			data, ok, innerErr := r.jsonAPIData(p.Data)
+			if innerErr != nil {
+				err = innerErr
+				return
+			}
+
+			if ok {
+				doc.Data = data
+			} else {
+				doc.Meta["data"] = p.Data
+			}
+		}
+
+		return
+	}
+
+	status := strconv.Itoa(responseCode)
+	errs := p.Errors
+	if len(errs) == 0 {
+		errs = []ErrorPayload{p.ErrorData}
+	}
+
+	for _, ep := range errs {
+		doc.Errors = append(doc.Errors, newJSONAPIErrors(ep, status)...)
+	}
+
+	if p.Data != nil {
+		doc.Meta["data"] = p.Data
+	}
+
+	return
+}
+
+// newJSONAPIErrors builds JSON:API error objects from an ErrorPayload. An error
+// object is built for each field that failed validation, if any.
+func newJSONAPIErrors(ep ErrorPayload, status string) (errs []jsonAPIError) {
+	var meta map[string]interface{}
+	if len(ep.Details) > 0 {
+		meta = ep.Details
+	}
+
+	if len(ep.Fields) == 0 {
+		errs = append(errs, jsonAPIError{
+			Status: status,
+			Code:   ep.Code,
+			Title:  ep.Error,
+			Detail: ep.Message,
+			Meta:   meta,
+		})
+		return
+	}
+
+	for _, f := range ep.Fields {
+		errs = append(errs, jsonAPIError{
+			Status: status,
+			Code:   ep.Code,
+			Title:  ep.Error,
+			Detail: f.Message,
+			Source: &jsonAPIErrorSource{Pointer: "/data/attributes/" + f.Field},
+			Meta:   map[string]interface{}{"rule": f.Rule},
+		})
+	}
+
+	return
+}
+
+// jsonAPIData returns data as a resource object, or an array of resource objects if
+// data is a slice. False is returned if the type and id of data, or any element of
+// data, cannot be determined.
+func (r *Responder) jsonAPIData(data interface{}) (res interface{}, ok bool, err error) {
+	v := reflect.ValueOf(data)
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+		resources := make([]jsonAPIResource, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			rsc, elemOK, innerErr := r.jsonAPIResourceFor(v.Index(i).Interface())
+			if innerErr != nil || !elemOK {
+				err = innerErr
+				return
+			}
+
+			resources = append(resources, rsc)
+		}
+
+		return resources, true, nil
+	}
+
+	rsc, ok, err := r.jsonAPIResourceFor(data)
+	if err != nil || !ok {
+		return
+	}
+
+	return rsc, true, nil
+}
+
+// jsonAPIResourceFor returns v as a resource object. False is returned if the type
+// and id of v cannot be determined.
+func (r *Responder) jsonAPIResourceFor(v interface{}) (rsc jsonAPIResource, ok bool, err error) {
+	switch t := v.(type) {
+	case JSONAPIResource:
+		rsc.Type, rsc.ID, ok = t.JSONAPIType(), t.JSONAPIID(), true
+	default:
+		if r.jsonAPIResource != nil {
+			rsc.Type, rsc.ID, ok = r.jsonAPIResource(v)
+		}
+	}
+	if !ok {
+		return
+	}
+
+	//The id and type members are not allowed in attributes since they are part of
+	//the resource object itself.
+	g, err := toGeneric(v)
+	if err != nil {
+		return
+	}
+
+	if m, isMap := g.(genericMap); isMap {
+		attrs := genericMap{}
+		for _, member := range m {
+			if member.Key != "id" && member.Key != "type" {
+				attrs = append(attrs, member)
+			}
+		}
+		rsc.Attributes = attrs
+	} else if g != nil {
+		rsc.Attributes = genericMap{{Key: "value", Value: g}}
+	}
+
+	return
+}
+
+// jsonAPIContentType is the content type of JSON:API documents.
+const jsonAPIContentType = "application/vnd.api+json"
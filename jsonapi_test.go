@@ -0,0 +1,94 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type jsonAPIUser struct {
+	Name string `json:"name"`
+}
+
+func (u jsonAPIUser) JSONAPIType() string { return "users" }
+func (u jsonAPIUser) JSONAPIID() string   { return u.Name }
+
+func TestJSONAPIDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		send     func(r *Responder, w *httptest.ResponseRecorder)
+		wantData interface{}
+		wantMeta map[string]interface{}
+		wantErrs int
+	}{
+		{
+			name: "resource",
+			send: func(r *Responder, w *httptest.ResponseRecorder) {
+				r.DataFound(jsonAPIUser{Name: "a"}, w)
+			},
+			wantData: map[string]interface{}{"type": "users", "id": "a", "attributes": map[string]interface{}{"name": "a"}},
+			wantMeta: map[string]interface{}{"messageType": "dataFound"},
+		},
+		{
+			name: "not a resource",
+			send: func(r *Responder, w *httptest.ResponseRecorder) {
+				r.DataFound(1, w)
+			},
+			wantMeta: map[string]interface{}{"messageType": "dataFound", "data": float64(1)},
+		},
+		{
+			name: "envelope fields",
+			opts: []Option{WithAPIVersion("v2"), WithSequence(true)},
+			send: func(r *Responder, w *httptest.ResponseRecorder) {
+				p := Payload{OK: true, Type: "dataFound", TraceID: "t1", SpanID: "s1", DurationMS: 1.5}
+				r.Send(p, w, 200)
+			},
+			wantMeta: map[string]interface{}{
+				"messageType": "dataFound",
+				"traceID":     "t1",
+				"spanID":      "s1",
+				"durationMS":  1.5,
+				"apiVersion":  "v2",
+				"sequence":    float64(1),
+			},
+		},
+		{
+			name: "error",
+			send: func(r *Responder, w *httptest.ResponseRecorder) {
+				r.Error(errors.New("bad"), "Bad.", w)
+			},
+			wantMeta: map[string]interface{}{"messageType": "error"},
+			wantErrs: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(append([]Option{WithJSONAPI(true), WithOmitDatetime(true)}, tt.opts...)...)
+			w := httptest.NewRecorder()
+			tt.send(r, w)
+
+			var doc struct {
+				Data   interface{}
+				Errors []interface{}
+				Meta   map[string]interface{}
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+				t.Fatalf("could not decode %q: %v", w.Body.String(), err)
+			}
+
+			if !reflect.DeepEqual(doc.Data, tt.wantData) {
+				t.Fatalf("got data %#v, want %#v", doc.Data, tt.wantData)
+			}
+			if !reflect.DeepEqual(doc.Meta, tt.wantMeta) {
+				t.Fatalf("got meta %#v, want %#v", doc.Meta, tt.wantMeta)
+			}
+			if len(doc.Errors) != tt.wantErrs {
+				t.Fatalf("got %d errors, want %d", len(doc.Errors), tt.wantErrs)
+			}
+		})
+	}
+}
@@ -0,0 +1,435 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSONSchema is a parsed JSON Schema used to validate Data, see WithSchema.
+//
+// The commonly used validation keywords are supported: type, enum, const,
+// properties, required, additionalProperties, items, minItems, maxItems,
+// minLength, maxLength, minimum, maximum, pattern, allOf, anyOf, oneOf, and not.
+// Other keywords, such as format and descriptive keywords, are ignored. $ref is not
+// supported and results in an error when parsing.
+type JSONSchema struct {
+	//never is true for the schema false, which nothing matches.
+	never bool
+
+	types      []string
+	enum       []string
+	properties map[string]*JSONSchema
+	required   []string
+
+	additionalProperties *JSONSchema
+	items                *JSONSchema
+
+	minItems, maxItems   *int
+	minLength, maxLength *int
+	minimum, maximum     *float64
+	pattern              *regexp.Regexp
+
+	allOf, anyOf, oneOf []*JSONSchema
+	not                 *JSONSchema
+}
+
+// ParseJSONSchema parses a JSON Schema.
+//
+//	s, err := output.ParseJSONSchema([]byte(`{
+//		"type": "object",
+//		"required": ["ID", "Name"],
+//		"properties": {
+//			"ID":   {"type": "integer"},
+//			"Name": {"type": "string"}
+//		}
+//	}`))
+func ParseJSONSchema(b []byte) (s *JSONSchema, err error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	err = dec.Decode(&v)
+	if err != nil {
+		err = fmt.Errorf("output: invalid JSON schema: %w", err)
+		return
+	}
+
+	s, err = parseJSONSchema(v, "")
+	return
+}
+
+// parseJSONSchema parses the decoded schema v found at path.
+func parseJSONSchema(v interface{}, path string) (s *JSONSchema, err error) {
+	invalid := func(keyword, msg string) error {
+		return fmt.Errorf("output: invalid JSON schema at %s/%s: %s", path, keyword, msg)
+	}
+
+	s = &JSONSchema{}
+
+	if b, ok := v.(bool); ok {
+		s.never = !b
+		return
+	}
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		err = invalid("", "schema must be an object or boolean")
+		return
+	}
+
+	if _, ok := m["$ref"]; ok {
+		err = invalid("$ref", "$ref is not supported")
+		return
+	}
+
+	//Parse the keywords in a consistent order so errors are reproducible.
+	keywords := make([]string, 0, len(m))
+	for k := range m {
+		keywords = append(keywords, k)
+	}
+	sort.Strings(keywords)
+
+	for _, k := range keywords {
+		kv := m[k]
+
+		switch k {
+		case "type":
+			switch t := kv.(type) {
+			case string:
+				s.types = []string{t}
+			case []interface{}:
+				for _, e := range t {
+					str, ok := e.(string)
+					if !ok {
+						return nil, invalid(k, "must be a string or an array of strings")
+					}
+					s.types = append(s.types, str)
+				}
+			default:
+				return nil, invalid(k, "must be a string or an array of strings")
+			}
+
+		case "enum":
+			a, ok := kv.([]interface{})
+			if !ok {
+				return nil, invalid(k, "must be an array")
+			}
+			for _, e := range a {
+				s.enum = append(s.enum, schemaValueKey(e))
+			}
+
+		case "const":
+			s.enum = []string{schemaValueKey(kv)}
+
+		case "properties":
+			props, ok := kv.(map[string]interface{})
+			if !ok {
+				return nil, invalid(k, "must be an object")
+			}
+			s.properties = make(map[string]*JSONSchema, len(props))
+			for name, pv := range props {
+				s.properties[name], err = parseJSONSchema(pv, path+"/properties/"+name)
+				if err != nil {
+					return
+				}
+			}
+
+		case "required":
+			a, ok := kv.([]interface{})
+			if !ok {
+				return nil, invalid(k, "must be an array of strings")
+			}
+			for _, e := range a {
+				str, ok := e.(string)
+				if !ok {
+					return nil, invalid(k, "must be an array of strings")
+				}
+				s.required = append(s.required, str)
+			}
+
+		case "additionalProperties", "items", "not":
+			var sub *JSONSchema
+			sub, err = parseJSONSchema(kv, path+"/"+k)
+			if err != nil {
+				return
+			}
+
+			switch k {
+			case "additionalProperties":
+				s.additionalProperties = sub
+			case "items":
+				s.items = sub
+			default:
+				s.not = sub
+			}
+
+		case "allOf", "anyOf", "oneOf":
+			a, ok := kv.([]interface{})
+			if !ok || len(a) == 0 {
+				return nil, invalid(k, "must be a non-empty array of schemas")
+			}
+
+			subs := make([]*JSONSchema, len(a))
+			for i, e := range a {
+				subs[i], err = parseJSONSchema(e, path+"/"+k+"/"+strconv.Itoa(i))
+				if err != nil {
+					return
+				}
+			}
+
+			switch k {
+			case "allOf":
+				s.allOf = subs
+			case "anyOf":
+				s.anyOf = subs
+			default:
+				s.oneOf = subs
+			}
+
+		case "minItems", "maxItems", "minLength", "maxLength":
+			n, ok := kv.(json.Number)
+			i, convErr := n.Int64()
+			if !ok || convErr != nil || i < 0 {
+				return nil, invalid(k, "must be a non-negative integer")
+			}
+			limit := int(i)
+
+			switch k {
+			case "minItems":
+				s.minItems = &limit
+			case "maxItems":
+				s.maxItems = &limit
+			case "minLength":
+				s.minLength = &limit
+			default:
+				s.maxLength = &limit
+			}
+
+		case "minimum", "maximum":
+			n, ok := kv.(json.Number)
+			f, convErr := n.Float64()
+			if !ok || convErr != nil {
+				return nil, invalid(k, "must be a number")
+			}
+
+			if k == "minimum" {
+				s.minimum = &f
+			} else {
+				s.maximum = &f
+			}
+
+		case "pattern":
+			str, ok := kv.(string)
+			if !ok {
+				return nil, invalid(k, "must be a string")
+			}
+			s.pattern, err = regexp.Compile(str)
+			if err != nil {
+				return nil, invalid(k, err.Error())
+			}
+		}
+	}
+
+	return
+}
+
+// Validate checks if data matches the schema. data is validated based on its JSON
+// encoding. The returned error lists each mismatch, with the path to the
+// mismatched value, such as "/Items/0/Name: expected string, got number".
+func (s *JSONSchema) Validate(data interface{}) (err error) {
+	g, err := toGeneric(data)
+	if err != nil {
+		return
+	}
+
+	if problems := s.validate(g, ""); len(problems) > 0 {
+		err = errors.New(strings.Join(problems, "; "))
+	}
+
+	return
+}
+
+// validate returns the mismatches between the generic value g, found at path, and
+// the schema.
+func (s *JSONSchema) validate(g interface{}, path string) (problems []string) {
+	fail := func(format string, args ...interface{}) {
+		p := path
+		if p == "" {
+			p = "/"
+		}
+		problems = append(problems, p+": "+fmt.Sprintf(format, args...))
+	}
+
+	if s.never {
+		fail("no value is allowed")
+		return
+	}
+
+	kind := schemaKind(g)
+	if len(s.types) > 0 && !s.allowsKind(kind) {
+		fail("expected %s, got %s", strings.Join(s.types, " or "), kind)
+		return
+	}
+
+	if len(s.enum) > 0 {
+		key, found := schemaValueKey(g), false
+		for _, e := range s.enum {
+			if e == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value is not one of the allowed values")
+		}
+	}
+
+	switch t := g.(type) {
+	case genericMap:
+		for _, name := range s.required {
+			if !t.has(name) {
+				fail("missing required property %q", name)
+			}
+		}
+
+		for _, member := range t {
+			memberPath := path + "/" + member.Key
+			if ps, ok := s.properties[member.Key]; ok {
+				problems = append(problems, ps.validate(member.Value, memberPath)...)
+			} else if s.additionalProperties != nil {
+				problems = append(problems, s.additionalProperties.validate(member.Value, memberPath)...)
+			}
+		}
+
+	case []interface{}:
+		if s.minItems != nil && len(t) < *s.minItems {
+			fail("expected at least %d items, got %d", *s.minItems, len(t))
+		}
+		if s.maxItems != nil && len(t) > *s.maxItems {
+			fail("expected at most %d items, got %d", *s.maxItems, len(t))
+		}
+		if s.items != nil {
+			for i, e := range t {
+				problems = append(problems, s.items.validate(e, path+"/"+strconv.Itoa(i))...)
+			}
+		}
+
+	case string:
+		n := utf8.RuneCountInString(t)
+		if s.minLength != nil && n < *s.minLength {
+			fail("expected at least %d characters, got %d", *s.minLength, n)
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			fail("expected at most %d characters, got %d", *s.maxLength, n)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(t) {
+			fail("does not match pattern %q", s.pattern.String())
+		}
+
+	case json.Number:
+		f, _ := t.Float64()
+		if s.minimum != nil && f < *s.minimum {
+			fail("expected a minimum of %v, got %s", *s.minimum, t)
+		}
+		if s.maximum != nil && f > *s.maximum {
+			fail("expected a maximum of %v, got %s", *s.maximum, t)
+		}
+	}
+
+	for _, sub := range s.allOf {
+		problems = append(problems, sub.validate(g, path)...)
+	}
+
+	if len(s.anyOf) > 0 && s.countMatches(s.anyOf, g, path) == 0 {
+		fail("does not match any of the allowed schemas")
+	}
+
+	if len(s.oneOf) > 0 {
+		if n := s.countMatches(s.oneOf, g, path); n != 1 {
+			fail("expected to match exactly one schema, matched %d", n)
+		}
+	}
+
+	if s.not != nil && len(s.not.validate(g, path)) == 0 {
+		fail("matches a schema that is not allowed")
+	}
+
+	return
+}
+
+// countMatches returns the number of schemas in subs that g matches.
+func (s *JSONSchema) countMatches(subs []*JSONSchema, g interface{}, path string) (n int) {
+	for _, sub := range subs {
+		if len(sub.validate(g, path)) == 0 {
+			n++
+		}
+	}
+
+	return
+}
+
+// allowsKind reports if the schema's types allow values of kind. Integers are also
+// numbers.
+func (s *JSONSchema) allowsKind(kind string) bool {
+	for _, t := range s.types {
+		if t == kind || (t == "number" && kind == "integer") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// schemaKind returns the JSON Schema type of the generic value g.
+func schemaKind(g interface{}) string {
+	switch t := g.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case genericMap:
+		return "object"
+	case []interface{}:
+		return "array"
+	case json.Number:
+		if f, err := t.Float64(); err == nil && f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return "unknown"
+	}
+}
+
+// schemaValueKey returns a string used to compare v, a generic value or a value
+// decoded from a schema, for equality with enum and const. Objects are compared
+// regardless of the order of their keys.
+func schemaValueKey(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	return string(canonicalJSON(b))
+}
+
+// has reports if m has a member with the key k.
+func (m genericMap) has(k string) bool {
+	for _, member := range m {
+		if member.Key == k {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,130 @@
+package output
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// JWEKeyFunc returns the public key, and its ID, used to encrypt the Data of the
+// response to req. This allows each client to be sent Data encrypted with its own
+// key. Return a nil key to send Data unencrypted, for example for clients that are
+// trusted. req is nil if the ResponseWriter does not carry the request, see
+// Negotiate.
+type JWEKeyFunc func(req *http.Request) (key crypto.PublicKey, kid string, err error)
+
+// ErrUnsupportedJWEKey is returned when a JWEKeyFunc returns a key of an unsupported
+// type.
+var ErrUnsupportedJWEKey = errors.New("output: unsupported JWE key type")
+
+// WithEncryptedData causes the Data of every response to be encrypted as a compact
+// JWE, using the key returned by fn, while the rest of the Payload is left readable.
+// This is useful for endpoints that return PII to third parties through shared
+// infrastructure, such as proxies or logging, that should not see the Data.
+//
+// Data is encoded as JSON, encrypted with A256GCM, and the content encryption key
+// is encrypted with RSA-OAEP-256. The key must be an *rsa.PublicKey. The JWE, a
+// string, replaces Data. If Data cannot be encrypted nothing is sent and the error
+// is returned.
+func WithEncryptedData(fn JWEKeyFunc) Option {
+	return func(r *Responder) {
+		r.jweKeyFunc = fn
+	}
+}
+
+// jweHeader is the JWE protected header.
+type jweHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Kid string `json:"kid,omitempty"`
+	Cty string `json:"cty"`
+}
+
+// encryptData replaces p's Data with a JWE of the Data, if a key is returned for the
+// response being sent to w.
+func (r *Responder) encryptData(p *Payload, w http.ResponseWriter) (err error) {
+	if r.jweKeyFunc == nil || p.Data == nil {
+		return
+	}
+
+	key, kid, err := r.jweKeyFunc(requestFrom(w))
+	if err != nil || key == nil {
+		return
+	}
+
+	pub, ok := key.(*rsa.PublicKey)
+	if !ok {
+		err = ErrUnsupportedJWEKey
+		return
+	}
+
+	plaintext, err := json.Marshal(p.Data)
+	if err != nil {
+		return
+	}
+
+	p.Data, err = encryptJWE(plaintext, pub, kid)
+	return
+}
+
+// encryptJWE returns plaintext encrypted as a compact JWE using RSA-OAEP-256 and
+// A256GCM.
+func encryptJWE(plaintext []byte, pub *rsa.PublicKey, kid string) (jwe string, err error) {
+	hj, err := json.Marshal(jweHeader{
+		Alg: "RSA-OAEP-256",
+		Enc: "A256GCM",
+		Kid: kid,
+		Cty: "json",
+	})
+	if err != nil {
+		return
+	}
+
+	enc := base64.RawURLEncoding
+	protected := enc.EncodeToString(hj)
+
+	//Generate the content encryption key and encrypt it for the recipient.
+	cek := make([]byte, 32)
+	if _, err = rand.Read(cek); err != nil {
+		return
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, cek, nil)
+	if err != nil {
+		return
+	}
+
+	//Encrypt the plaintext. The protected header is the additional authenticated
+	//data and GCM's tag is appended to the ciphertext.
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return
+	}
+
+	iv := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(iv); err != nil {
+		return
+	}
+
+	sealed := gcm.Seal(nil, iv, plaintext, []byte(protected))
+	ciphertext, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	jwe = protected + "." +
+		enc.EncodeToString(encryptedKey) + "." +
+		enc.EncodeToString(iv) + "." +
+		enc.EncodeToString(ciphertext) + "." +
+		enc.EncodeToString(tag)
+	return
+}
@@ -0,0 +1,238 @@
+package output
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"mime"
+	"strings"
+)
+
+// JWSSerialization is the format of a JWS, see WithJWS.
+type JWSSerialization int
+
+const (
+	//JWSCompact sends the JWS as header.payload.signature with the content type
+	//application/jose.
+	JWSCompact JWSSerialization = iota
+
+	//JWSJSON sends the JWS as a JSON object, using the flattened JSON
+	//serialization, with the content type application/jose+json.
+	JWSJSON
+)
+
+// JWSSigner signs responses wrapped in a JWS. Implement this to provide your own key
+// management, such as keys stored in a KMS or keys that are rotated, returning the
+// ID of the key used from KeyID so that clients can look up the matching public
+// key. See NewJWSSigner and NewJWSHMACSigner for signers using keys held in memory.
+type JWSSigner interface {
+	//Algorithm returns the JWS "alg" header value, for example "ES256".
+	Algorithm() string
+
+	//KeyID returns the JWS "kid" header value. Return a blank string to omit it.
+	KeyID() string
+
+	//Sign returns the signature of signingInput, encoded as required for the
+	//algorithm by RFC 7518.
+	Sign(signingInput []byte) (sig []byte, err error)
+}
+
+// ErrUnsupportedJWSKey is returned when a key of an unsupported type is provided to
+// NewJWSSigner.
+var ErrUnsupportedJWSKey = errors.New("output: unsupported JWS key type")
+
+// WithJWS causes JSON responses to be wrapped in a JWS signed by signer, using the
+// serialization s, for integrations that require non-repudiation of responses. The
+// encoded Payload is the JWS payload and is not otherwise altered.
+//
+// If the response cannot be signed nothing is sent and the error is returned, since
+// sending an unsigned response would defeat the purpose of signing.
+func WithJWS(signer JWSSigner, s JWSSerialization) Option {
+	return func(r *Responder) {
+		r.jwsSigner = signer
+		r.jwsSerialization = s
+	}
+}
+
+// jwsHeader is the JWS protected header.
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ"`
+	Cty string `json:"cty,omitempty"`
+}
+
+// jwsFlattened is the flattened JWS JSON serialization.
+type jwsFlattened struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// jws wraps body, encoded as contentType, in a JWS. The JWS and its content type are
+// returned.
+func (r *Responder) jws(body []byte, contentType string) (out []byte, jwsContentType string, err error) {
+	h := jwsHeader{
+		Alg: r.jwsSigner.Algorithm(),
+		Kid: r.jwsSigner.KeyID(),
+		Typ: "JOSE",
+	}
+	if r.jwsSerialization == JWSJSON {
+		h.Typ = "JOSE+JSON"
+	}
+
+	//The "application/" prefix is omitted from the payload's content type as
+	//recommended by RFC 7515.
+	if mt, _, parseErr := mime.ParseMediaType(contentType); parseErr == nil {
+		h.Cty = strings.TrimPrefix(mt, "application/")
+	}
+
+	hj, err := json.Marshal(h)
+	if err != nil {
+		return
+	}
+
+	enc := base64.RawURLEncoding
+	protected := enc.EncodeToString(hj)
+	payload := enc.EncodeToString(body)
+
+	sig, err := r.jwsSigner.Sign([]byte(protected + "." + payload))
+	if err != nil {
+		return
+	}
+	signature := enc.EncodeToString(sig)
+
+	switch r.jwsSerialization {
+	case JWSJSON:
+		out, err = json.Marshal(jwsFlattened{
+			Protected: protected,
+			Payload:   payload,
+			Signature: signature,
+		})
+		jwsContentType = "application/jose+json"
+	default:
+		out = []byte(protected + "." + payload + "." + signature)
+		jwsContentType = "application/jose"
+	}
+
+	return
+}
+
+// hmacSigner is a JWSSigner using HMAC-SHA256.
+type hmacSigner struct {
+	key []byte
+	kid string
+}
+
+// NewJWSHMACSigner returns a JWSSigner that signs with HMAC-SHA256, the HS256
+// algorithm, using key.
+func NewJWSHMACSigner(key []byte, kid string) JWSSigner {
+	return hmacSigner{
+		key: append([]byte(nil), key...),
+		kid: kid,
+	}
+}
+
+// Algorithm implements JWSSigner.
+func (s hmacSigner) Algorithm() string {
+	return "HS256"
+}
+
+// KeyID implements JWSSigner.
+func (s hmacSigner) KeyID() string {
+	return s.kid
+}
+
+// Sign implements JWSSigner.
+func (s hmacSigner) Sign(signingInput []byte) (sig []byte, err error) {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(signingInput)
+	sig = mac.Sum(nil)
+	return
+}
+
+// keySigner is a JWSSigner using a crypto.Signer.
+type keySigner struct {
+	key crypto.Signer
+	kid string
+	alg string
+}
+
+// NewJWSSigner returns a JWSSigner that signs with key. The algorithm is chosen
+// based on the type of key: RS256 for RSA keys, ES256 for ECDSA P-256 keys, and
+// EdDSA for Ed25519 keys. Since key is a crypto.Signer, it can be backed by a
+// hardware module or KMS. ErrUnsupportedJWSKey is returned for other types of keys.
+func NewJWSSigner(key crypto.Signer, kid string) (s JWSSigner, err error) {
+	ks := keySigner{
+		key: key,
+		kid: kid,
+	}
+
+	switch pub := key.Public().(type) {
+	case *rsa.PublicKey:
+		ks.alg = "RS256"
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			err = ErrUnsupportedJWSKey
+			return
+		}
+		ks.alg = "ES256"
+	case ed25519.PublicKey:
+		ks.alg = "EdDSA"
+	default:
+		err = ErrUnsupportedJWSKey
+		return
+	}
+
+	s = ks
+	return
+}
+
+// Algorithm implements JWSSigner.
+func (s keySigner) Algorithm() string {
+	return s.alg
+}
+
+// KeyID implements JWSSigner.
+func (s keySigner) KeyID() string {
+	return s.kid
+}
+
+// Sign implements JWSSigner.
+func (s keySigner) Sign(signingInput []byte) (sig []byte, err error) {
+	//Ed25519 signs the message itself, not a digest.
+	if s.alg == "EdDSA" {
+		sig, err = s.key.Sign(rand.Reader, signingInput, crypto.Hash(0))
+		return
+	}
+
+	digest := sha256.Sum256(signingInput)
+	sig, err = s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil || s.alg != "ES256" {
+		return
+	}
+
+	//crypto.Signer returns ECDSA signatures ASN.1 encoded but JWS requires the
+	//fixed length concatenation of R and S.
+	var rs struct {
+		R, S *big.Int
+	}
+	if _, err = asn1.Unmarshal(sig, &rs); err != nil {
+		return
+	}
+
+	sig = make([]byte, 64)
+	rs.R.FillBytes(sig[:32])
+	rs.S.FillBytes(sig[32:])
+	return
+}
@@ -0,0 +1,218 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// KeyCase is the casing used for the keys of a Payload when encoded.
+type KeyCase int
+
+// Casings that can be used with WithKeyCase.
+const (
+	//KeyCaseDefault uses the names of the Payload's fields, for example
+	//"ErrorData". This is the default.
+	KeyCaseDefault KeyCase = iota
+
+	//KeyCaseCamel uses lower camel case, for example "errorData".
+	KeyCaseCamel
+
+	//KeyCaseSnake uses snake case, for example "error_data".
+	KeyCaseSnake
+)
+
+// WithKeyCase sets the casing of the keys of each Payload, for example so that the
+// keys match a front-end style guide that requires lowercase keys. This applies to
+// the keys of the Payload, and the keys of data defined in this package such as
+// ErrorData, but not to the keys of Data, Meta, or ErrorData's Details since those
+// are user-defined.
+//
+// This applies to the JSON, MessagePack, and CBOR Encoders, and any other Encoder
+// based on JSON. The XML Encoder applies this to the Payload's keys only.
+func WithKeyCase(c KeyCase) Option {
+	return func(r *Responder) {
+		r.keyCase = c
+	}
+}
+
+// WithFieldNames sets the keys used for fields of each Payload, keyed by field
+// name, so that services migrating from other frameworks can keep their existing
+// client contract. Fields not provided use the default key, per WithKeyCase.
+//
+//	r := output.New(output.WithFieldNames(map[string]string{
+//		"OK":        "success",
+//		"Type":      "kind",
+//		"ErrorData": "error",
+//	}))
+//
+// This applies to the same Encoders as WithKeyCase.
+func WithFieldNames(names map[string]string) Option {
+	return func(r *Responder) {
+		if r.fieldNames == nil {
+			r.fieldNames = make(map[string]string, len(names))
+		}
+
+		for field, name := range names {
+			r.fieldNames[field] = name
+		}
+	}
+}
+
+// envelopeKeys describes how the keys of a Payload are encoded. A nil envelopeKeys
+// uses the default keys.
+type envelopeKeys struct {
+	//keyCase is the casing of each key.
+	keyCase KeyCase
+
+	//names are the keys used for fields of the Payload, keyed by field name. This
+	//takes precedence over keyCase.
+	names map[string]string
+}
+
+// newEnvelopeKeys returns the envelopeKeys for the Responder's configuration, or nil
+// if the default keys are used.
+func (r *Responder) newEnvelopeKeys() *envelopeKeys {
+	names := make(map[string]string, len(r.fieldNames)+1)
+	for field, name := range r.fieldNames {
+		names[field] = name
+	}
+	if r.datetimeField != "" && r.datetimeField != "Datetime" {
+		names["Datetime"] = r.datetimeField
+	}
+
+	if r.keyCase == KeyCaseDefault && len(names) == 0 {
+		return nil
+	}
+
+	return &envelopeKeys{
+		keyCase: r.keyCase,
+		names:   names,
+	}
+}
+
+// key returns the key for a field of the Payload with the provided name, as encoded
+// by default.
+func (ek *envelopeKeys) key(field, name string) string {
+	if ek == nil {
+		return name
+	}
+
+	if n, ok := ek.names[field]; ok {
+		return n
+	}
+
+	return convertCase(name, ek.keyCase)
+}
+
+// userKeys are the keys whose values are user-defined. The keys within these values
+// are not altered.
+var userKeys = map[string]bool{
+	"Data":    true,
+	"Meta":    true,
+	"Details": true,
+	"Related": true,
+}
+
+// apply alters the keys of the generic form of a Payload, see toGeneric.
+func (ek *envelopeKeys) apply(m genericMap) {
+	for i := range m {
+		if !userKeys[m[i].Key] {
+			recase(m[i].Value, ek.keyCase)
+		}
+
+		m[i].Key = ek.key(m[i].Key, m[i].Key)
+	}
+}
+
+// recase alters the casing of the keys within g, a generic value.
+func recase(g interface{}, c KeyCase) {
+	switch t := g.(type) {
+	case genericMap:
+		for i := range t {
+			if !userKeys[t[i].Key] {
+				recase(t[i].Value, c)
+			}
+
+			t[i].Key = convertCase(t[i].Key, c)
+		}
+	case []interface{}:
+		for _, v := range t {
+			recase(v, c)
+		}
+	}
+}
+
+// convertCase converts name, a Go style field name such as "RequestID", to c.
+func convertCase(name string, c KeyCase) string {
+	switch c {
+	case KeyCaseCamel:
+		words := splitWords(name)
+		for i, w := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(w)
+			}
+		}
+		return strings.Join(words, "")
+
+	case KeyCaseSnake:
+		return strings.ToLower(strings.Join(splitWords(name), "_"))
+
+	default:
+		return name
+	}
+}
+
+// splitWords splits a Go style field name into words. Runs of uppercase letters are
+// treated as initialisms, for example "APIVersion" is split into "API" and
+// "Version".
+func splitWords(name string) (words []string) {
+	runes := []rune(name)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+
+		//A new word starts at an uppercase letter following a lowercase letter or
+		//digit, or at the last uppercase letter of an initialism followed by a
+		//lowercase letter.
+		lowerToUpper := (unicode.IsLower(prev) || unicode.IsDigit(prev)) && unicode.IsUpper(cur)
+		initialismEnd := unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || initialismEnd {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	return append(words, string(runes[start:]))
+}
+
+// payloadJSON is used to encode a Payload without recursing into MarshalJSON.
+type payloadJSON Payload
+
+// MarshalJSON implements json.Marshaler. This allows the keys of the Payload to be
+// altered, see WithKeyCase, WithFieldNames, and WithDatetimeField.
+func (p Payload) MarshalJSON() ([]byte, error) {
+	//Most Payloads are simple enough to be encoded without reflection.
+	if b, ok := appendPayload(make([]byte, 0, fastPayloadSize), p); ok {
+		return b, nil
+	}
+
+	b, err := json.Marshal(payloadJSON(p))
+	if err != nil || p.keys == nil {
+		return b, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	g, err := decodeGeneric(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	m := g.(genericMap)
+	p.keys.apply(m)
+
+	return json.Marshal(m)
+}
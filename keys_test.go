@@ -0,0 +1,120 @@
+package output
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestConvertCase(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantCamel string
+		wantSnake string
+	}{
+		{"OK", "ok", "ok"},
+		{"Type", "type", "type"},
+		{"ErrorData", "errorData", "error_data"},
+		{"RequestID", "requestID", "request_id"},
+		{"APIVersion", "apiVersion", "api_version"},
+		{"DurationMS", "durationMS", "duration_ms"},
+		{"RetryAfterSeconds", "retryAfterSeconds", "retry_after_seconds"},
+		{"DocsURL", "docsURL", "docs_url"},
+		{"Base64Data", "base64Data", "base64_data"},
+		{"already_snake", "already_snake", "already_snake"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convertCase(tt.name, KeyCaseDefault); got != tt.name {
+				t.Fatalf("got default %q, want %q", got, tt.name)
+			}
+			if got := convertCase(tt.name, KeyCaseCamel); got != tt.wantCamel {
+				t.Fatalf("got camel %q, want %q", got, tt.wantCamel)
+			}
+			if got := convertCase(tt.name, KeyCaseSnake); got != tt.wantSnake {
+				t.Fatalf("got snake %q, want %q", got, tt.wantSnake)
+			}
+		})
+	}
+}
+
+func TestKeyCase(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []Option
+		wantKeys      []string
+		wantErrorKeys []string
+	}{
+		{
+			name:          "default",
+			wantKeys:      []string{"Data", "Datetime", "ErrorData", "OK", "Type"},
+			wantErrorKeys: []string{"Code", "Error", "Message"},
+		},
+		{
+			name:          "camel",
+			opts:          []Option{WithKeyCase(KeyCaseCamel)},
+			wantKeys:      []string{"data", "datetime", "errorData", "ok", "type"},
+			wantErrorKeys: []string{"code", "error", "message"},
+		},
+		{
+			name:          "snake",
+			opts:          []Option{WithKeyCase(KeyCaseSnake)},
+			wantKeys:      []string{"data", "datetime", "error_data", "ok", "type"},
+			wantErrorKeys: []string{"code", "error", "message"},
+		},
+		{
+			name:          "field names",
+			opts:          []Option{WithKeyCase(KeyCaseSnake), WithFieldNames(map[string]string{"OK": "success", "ErrorData": "error"})},
+			wantKeys:      []string{"data", "datetime", "error", "success", "type"},
+			wantErrorKeys: []string{"code", "error", "message"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.opts...)
+
+			p := Payload{
+				Type:      "error",
+				Data:      map[string]string{"UserID": "1"},
+				ErrorData: ErrorPayload{Code: "notFound", Error: "not found", Message: "Not found."},
+			}
+			w := httptest.NewRecorder()
+			if err := r.Send(p, w, 404); err != nil {
+				t.Fatal(err)
+			}
+
+			var got map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if keys := sortedKeys(got); !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Fatalf("got keys %q, want %q", keys, tt.wantKeys)
+			}
+
+			var errorData, data map[string]json.RawMessage
+			json.Unmarshal(got[tt.wantKeys[2]], &errorData)
+			if keys := sortedKeys(errorData); !reflect.DeepEqual(keys, tt.wantErrorKeys) {
+				t.Fatalf("got ErrorData keys %q, want %q", keys, tt.wantErrorKeys)
+			}
+
+			//Data is user-defined so its keys are not altered.
+			json.Unmarshal(got[tt.wantKeys[0]], &data)
+			if _, ok := data["UserID"]; !ok {
+				t.Fatalf("got Data %v, want UserID key", data)
+			}
+		})
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]json.RawMessage) (keys []string) {
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return
+}
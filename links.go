@@ -0,0 +1,125 @@
+package output
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Links are hypermedia links describing the response's resource, related resources,
+// and the actions that can be taken on the resource. This provides a consistent
+// format for links instead of each service defining its own within Data.
+//
+// Use NewLinks to build Links with absolute URLs based on the request and WithLinks
+// to include them in a response.
+//
+//	links := output.NewLinks(r).
+//		AddRelated("orders", "/users/42/orders").
+//		AddAction("delete", http.MethodDelete, "/users/42")
+//
+//	output.DataFound(user, w, output.WithLinks(links))
+type Links struct {
+	//Self is the URL of the response's resource.
+	Self string `json:",omitempty"`
+
+	//Related are URLs of related resources, keyed by relation name.
+	Related map[string]string `json:",omitempty"`
+
+	//Actions are the actions that can be taken on the resource.
+	Actions []LinkAction `json:",omitempty"`
+
+	//base is used to resolve relative URLs.
+	base *url.URL
+}
+
+// LinkAction is an action that can be taken on a resource.
+type LinkAction struct {
+	//Name identifies the action, for example "delete" or "approve".
+	Name string
+
+	//Method is the HTTP method used to take the action.
+	Method string
+
+	//Href is the URL used to take the action.
+	Href string
+}
+
+// NewLinks returns Links with Self set to the absolute URL of req. Relative URLs
+// provided to AddRelated and AddAction are resolved against req's URL. req may be
+// nil, in which case Self is not set and URLs are used as provided. The URL's scheme
+// honors the X-Forwarded-Proto header so links are correct behind a TLS terminating
+// proxy.
+func NewLinks(req *http.Request) *Links {
+	l := &Links{}
+	if req != nil {
+		l.base = requestURL(req)
+		l.Self = l.base.String()
+	}
+
+	return l
+}
+
+// AddRelated adds a link to a related resource. Relative URLs are resolved against
+// the request's URL.
+func (l *Links) AddRelated(rel, href string) *Links {
+	if l.Related == nil {
+		l.Related = map[string]string{}
+	}
+
+	l.Related[rel] = l.resolve(href)
+	return l
+}
+
+// AddAction adds an action that can be taken on the resource. Relative URLs are
+// resolved against the request's URL.
+func (l *Links) AddAction(name, method, href string) *Links {
+	l.Actions = append(l.Actions, LinkAction{
+		Name:   name,
+		Method: method,
+		Href:   l.resolve(href),
+	})
+	return l
+}
+
+// resolve returns href as an absolute URL if a base URL is known.
+func (l *Links) resolve(href string) string {
+	if l.base == nil {
+		return href
+	}
+
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+
+	return l.base.ResolveReference(u).String()
+}
+
+// WithLinks sets the Payload's Links.
+func WithLinks(l *Links) SendOption {
+	return func(o *sendOptions) {
+		o.links = l
+	}
+}
+
+// requestURL returns the absolute URL of req. The scheme is taken from the
+// X-Forwarded-Proto header, when set by a proxy that terminates TLS, otherwise from
+// whether or not req was received over TLS.
+func requestURL(req *http.Request) *url.URL {
+	u := *req.URL
+	u.Host = req.Host
+	u.Scheme = "http"
+	if req.TLS != nil {
+		u.Scheme = "https"
+	}
+
+	//Only the first, client-facing, proxy's value is used when the request passed
+	//through more than one proxy.
+	proto, _, _ := strings.Cut(req.Header.Get("X-Forwarded-Proto"), ",")
+	switch proto = strings.ToLower(strings.TrimSpace(proto)); proto {
+	case "http", "https":
+		u.Scheme = proto
+	}
+
+	return &u
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		tls            bool
+		forwardedProto string
+		want           string
+	}{
+		{
+			name:   "http",
+			target: "http://example.com/users?page=1",
+			want:   "http://example.com/users?page=1",
+		},
+		{
+			name:   "tls",
+			target: "https://example.com/users",
+			tls:    true,
+			want:   "https://example.com/users",
+		},
+		{
+			name:           "forwarded https",
+			target:         "http://example.com/users",
+			forwardedProto: "https",
+			want:           "https://example.com/users",
+		},
+		{
+			name:           "forwarded http over tls",
+			target:         "https://example.com/users",
+			tls:            true,
+			forwardedProto: "http",
+			want:           "http://example.com/users",
+		},
+		{
+			name:           "forwarded by multiple proxies",
+			target:         "http://example.com/users",
+			forwardedProto: "HTTPS, http",
+			want:           "https://example.com/users",
+		},
+		{
+			name:           "forwarded invalid",
+			target:         "http://example.com/users",
+			forwardedProto: "javascript",
+			want:           "http://example.com/users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.TLS = nil
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			if tt.forwardedProto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.forwardedProto)
+			}
+
+			if got := requestURL(req).String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLinks(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/users/1", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+
+	l := NewLinks(req)
+	l.AddRelated("orders", "orders")
+	l.AddAction("delete", http.MethodDelete, "/users/1")
+
+	if want := "https://example.com/users/1"; l.Self != want {
+		t.Fatalf("got Self %q, want %q", l.Self, want)
+	}
+	if want := "https://example.com/users/orders"; l.Related["orders"] != want {
+		t.Fatalf("got related %q, want %q", l.Related["orders"], want)
+	}
+	if want := "https://example.com/users/1"; l.Actions[0].Href != want {
+		t.Fatalf("got action %q, want %q", l.Actions[0].Href, want)
+	}
+}
@@ -0,0 +1,64 @@
+package output
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// errMaintenance is the error sent when maintenance mode is enabled.
+var errMaintenance = errors.New("maintenance in progress")
+
+// defaultMaintenanceMsg is the Message sent when maintenance mode is enabled and no
+// message was provided.
+const defaultMaintenanceMsg = "This service is undergoing maintenance, please try again later."
+
+// Maintenance turns maintenance mode on or off for the default Responder. See
+// Responder.Maintenance.
+func Maintenance(on bool, msg string, retryAfter time.Duration) {
+	std.Maintenance(on, msg, retryAfter)
+}
+
+// Maintenance turns maintenance mode on or off. While on, every response sent by
+// the Responder is replaced with an HTTP status 503 error Payload using the
+// TypeMaintenance message type and msg as the error's Message. If retryAfter is
+// greater than zero, the Retry-After header is set.
+//
+// This is typically toggled from an admin endpoint or during deploys.
+func (r *Responder) Maintenance(on bool, msg string, retryAfter time.Duration) {
+	if msg == "" {
+		msg = defaultMaintenanceMsg
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.maintenance = on
+	r.maintenanceMsg = msg
+	r.maintenanceRetryAfter = retryAfter
+}
+
+// inMaintenance replaces the Payload, response code, and options being sent with
+// the maintenance response if maintenance mode is enabled. True is returned if
+// maintenance mode is enabled.
+func (r *Responder) inMaintenance(w http.ResponseWriter, p *Payload, responseCode *int, opts *[]SendOption) bool {
+	r.mu.RLock()
+	on, msg, retryAfter := r.maintenance, r.maintenanceMsg, r.maintenanceRetryAfter
+	r.mu.RUnlock()
+
+	if !on {
+		return false
+	}
+
+	*p = r.newPayload(false, TypeMaintenance, nil, r.newErrorPayload(errMaintenance, msg, w))
+	*responseCode = http.StatusServiceUnavailable
+
+	//Per-response options are ignored since the response is no longer what the
+	//caller intended.
+	*opts = nil
+	if retryAfter > 0 {
+		*opts = []SendOption{WithRetryAfter(retryAfter)}
+	}
+
+	return true
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"net/http"
+)
+
+// marshalErrorBody is sent when a response cannot be encoded. This is hardcoded so
+// that it can always be sent, regardless of what caused the failure.
+const marshalErrorBody = `{"OK":false,"Type":"error","ErrorData":{"Code":"marshalError","Error":"could not encode response","Message":"The response could not be encoded."}}`
+
+// MarshalErrorFunc is called when a response cannot be encoded, for example when
+// Data contains a channel, func, or NaN. p is the Payload that could not be encoded.
+// The request is nil unless the ResponseWriter carries the request, see Negotiate.
+type MarshalErrorFunc func(err error, p Payload, req *http.Request)
+
+// OnMarshalError registers a func called when a response cannot be encoded by the
+// default Responder. See Responder.OnMarshalError.
+func OnMarshalError(fn MarshalErrorFunc) {
+	std.OnMarshalError(fn)
+}
+
+// WithMarshalError registers a func called when a response cannot be encoded when
+// creating a Responder. See Responder.OnMarshalError.
+func WithMarshalError(fn MarshalErrorFunc) Option {
+	return func(r *Responder) {
+		r.OnMarshalError(fn)
+	}
+}
+
+// OnMarshalError registers a func called when a response cannot be encoded. When
+// this happens a minimal, hardcoded, HTTP status 500 error Payload is sent in place
+// of the response and the encoding error is returned. This allows the failure to be
+// surfaced, for example to an error tracker, since it is a bug in the app rather
+// than a problem with the request.
+func (r *Responder) OnMarshalError(fn MarshalErrorFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.marshalError = append(r.marshalError, fn)
+}
+
+// marshalFailed sends the hardcoded error Payload in place of a response that could
+// not be encoded, logs the error, and calls each MarshalErrorFunc.
+func (r *Responder) marshalFailed(w http.ResponseWriter, p *Payload, err error) {
+	r.logger.Error("output.send: could not encode response", "type", p.Type, "error", err)
+
+	w.Header().Set("Content-Type", JSON.ContentType())
+	w.Header().Del("Content-Length")
+	w.WriteHeader(http.StatusInternalServerError)
+	w.Write([]byte(marshalErrorBody))
+
+	r.mu.RLock()
+	hooks := r.marshalError
+	r.mu.RUnlock()
+
+	req := requestFrom(w)
+	for _, fn := range hooks {
+		fn(err, *p, req)
+	}
+}
+
+// headerWriter writes the response code to w when the body is first written. This
+// allows the response code to be changed if encoding fails before anything was
+// written.
+type headerWriter struct {
+	w     http.ResponseWriter
+	code  int
+	wrote bool
+
+	//n is the number of bytes written.
+	n int
+}
+
+// Write implements io.Writer.
+func (hw *headerWriter) Write(b []byte) (n int, err error) {
+	hw.writeHeader()
+
+	n, err = hw.w.Write(b)
+	hw.n += n
+	return
+}
+
+// writeHeader writes the response code if it has not been written yet.
+func (hw *headerWriter) writeHeader() {
+	if hw.wrote {
+		return
+	}
+
+	hw.wrote = true
+	hw.w.WriteHeader(hw.code)
+}
@@ -0,0 +1,106 @@
+package output
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RegisterMessageTypes defines message types that can be used with Success on the
+// default Responder when strict message types are enforced. See
+// EnforceStrictMessageTypes.
+func RegisterMessageTypes(msgTypes ...MessageType) {
+	std.RegisterMessageTypes(msgTypes...)
+}
+
+// EnforceStrictMessageTypes turns strict message types on or off for the default
+// Responder. When enabled, Success, and its wrapper funcs, return an error wrapping
+// ErrUndefinedMessageType if a message type that was not registered with
+// RegisterMessageTypes is used. This helps keep the message types clients need to
+// handle consistent across a large codebase.
+func EnforceStrictMessageTypes(b bool) {
+	std.EnforceStrictMessageTypes(b)
+}
+
+// RefuseUndefinedMessageTypes causes the default Responder to not send responses
+// using an undefined message type when strict message types are enforced. The error
+// is still returned but nothing is written to the ResponseWriter, so you must
+// handle the error and send a response yourself.
+func RefuseUndefinedMessageTypes(b bool) {
+	std.RefuseUndefinedMessageTypes(b)
+}
+
+// WithMessageTypes defines the message types that can be used with Success when
+// strict message types are enforced. The predefined message types are always
+// allowed.
+func WithMessageTypes(msgTypes ...MessageType) Option {
+	return func(r *Responder) {
+		r.RegisterMessageTypes(msgTypes...)
+	}
+}
+
+// WithStrictMessageTypes turns strict message types on or off. See
+// EnforceStrictMessageTypes.
+func WithStrictMessageTypes(b bool) Option {
+	return func(r *Responder) {
+		r.EnforceStrictMessageTypes(b)
+	}
+}
+
+// WithRefuseUndefinedMessageTypes causes responses using an undefined message type
+// to not be sent. See RefuseUndefinedMessageTypes.
+func WithRefuseUndefinedMessageTypes(b bool) Option {
+	return func(r *Responder) {
+		r.RefuseUndefinedMessageTypes(b)
+	}
+}
+
+// RegisterMessageTypes defines message types that can be used with Success when
+// strict message types are enforced.
+func (r *Responder) RegisterMessageTypes(msgTypes ...MessageType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range msgTypes {
+		r.messageTypes[t] = struct{}{}
+	}
+}
+
+// EnforceStrictMessageTypes turns strict message types on or off. See the
+// package-level EnforceStrictMessageTypes.
+func (r *Responder) EnforceStrictMessageTypes(b bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.strictMessageTypes = b
+}
+
+// RefuseUndefinedMessageTypes turns refusing to send undefined message types on or
+// off. See the package-level RefuseUndefinedMessageTypes.
+func (r *Responder) RefuseUndefinedMessageTypes(b bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refuseUndefinedMessageTypes = b
+}
+
+// checkMessageType returns an error if strict message types are enforced and
+// msgType was not defined. refuse is returned true if the response should not be
+// sent.
+func (r *Responder) checkMessageType(msgType MessageType, w http.ResponseWriter) (refuse bool, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.strictMessageTypes {
+		return
+	}
+
+	if _, ok := r.messageTypes[msgType]; ok {
+		return
+	}
+
+	r.log(w, "output.Success: undefined message type", "type", msgType)
+
+	err = fmt.Errorf("%w: %q is not registered", ErrUndefinedMessageType, msgType)
+	refuse = r.refuseUndefinedMessageTypes
+	return
+}
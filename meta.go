@@ -0,0 +1,44 @@
+package output
+
+// WithDefaultMeta sets metadata included in the Meta of every Payload sent by the
+// Responder, for example the name of the service. Keys set on a single response,
+// such as with WithMeta, take precedence over default metadata.
+func WithDefaultMeta(m map[string]interface{}) Option {
+	return func(r *Responder) {
+		if r.defaultMeta == nil {
+			r.defaultMeta = make(map[string]interface{}, len(m))
+		}
+
+		for k, v := range m {
+			r.defaultMeta[k] = v
+		}
+	}
+}
+
+// WithMetaValue sets a single key in the Payload's Meta. This is shorthand for
+// WithMeta with a one-key map.
+//
+//	output.DataFound(data, w, output.WithMetaValue("RateLimitRemaining", remaining))
+func WithMetaValue(key string, value interface{}) SendOption {
+	return WithMeta(map[string]interface{}{key: value})
+}
+
+// setDefaultMeta adds the Responder's default metadata to the Payload's Meta without
+// replacing existing keys.
+func (r *Responder) setDefaultMeta(p *Payload) {
+	if len(r.defaultMeta) == 0 {
+		return
+	}
+
+	//A new map is used so that neither the default metadata nor a map provided by
+	//the caller is modified.
+	meta := make(map[string]interface{}, len(r.defaultMeta)+len(p.Meta))
+	for k, v := range r.defaultMeta {
+		meta[k] = v
+	}
+	for k, v := range p.Meta {
+		meta[k] = v
+	}
+
+	p.Meta = meta
+}
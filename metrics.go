@@ -0,0 +1,21 @@
+package output
+
+import "time"
+
+// MetricsSink receives metrics about each response sent. This package does not
+// depend on a metrics library, instead implement MetricsSink for the metrics system
+// you use. See the outputstatsd package for a StatsD, or Datadog, MetricsSink.
+type MetricsSink interface {
+	//ObserveResponse is called after each response is sent with the Payload's
+	//Type, the HTTP status code, and the duration. The duration covers handling
+	//the request if TimingMiddleware is used, otherwise it only covers encoding
+	//and writing the response.
+	ObserveResponse(msgType string, responseCode int, duration time.Duration)
+}
+
+// WithMetrics sets the MetricsSink that receives metrics about each response sent.
+func WithMetrics(m MetricsSink) Option {
+	return func(r *Responder) {
+		r.metrics = m
+	}
+}
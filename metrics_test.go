@@ -0,0 +1,60 @@
+package output
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// durationSink records the duration of the last response.
+type durationSink struct {
+	duration time.Duration
+}
+
+func (s *durationSink) ObserveResponse(msgType string, responseCode int, duration time.Duration) {
+	s.duration = duration
+}
+
+func TestMetricsDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		timing  bool
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "without timing middleware",
+			wantMin: 0,
+			wantMax: time.Second,
+		},
+		{
+			name:    "with timing middleware",
+			elapsed: 5 * time.Second,
+			timing:  true,
+			wantMin: 5 * time.Second,
+			wantMax: 6 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &durationSink{}
+			r := New(WithMetrics(s))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.timing {
+				start := time.Now().Add(-tt.elapsed)
+				req = req.WithContext(context.WithValue(req.Context(), requestStartKey{}, start))
+			}
+
+			w := httptest.NewRecorder()
+			r.DataFound(1, Negotiate(w, req))
+
+			if s.duration < tt.wantMin || s.duration > tt.wantMax {
+				t.Fatalf("got duration %s, want between %s and %s", s.duration, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package output
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// MessagePack is an Encoder that encodes responses as MessagePack. The Payload, and
+// the Data stored in it, are encoded with the same field names as with JSON.
+var MessagePack Encoder = &genericEncoder{
+	contentType: "application/msgpack",
+	encode:      encodeMsgpack,
+}
+
+// encodeMsgpack writes the generic value g to w as MessagePack.
+func encodeMsgpack(w io.Writer, g interface{}) error {
+	bw := bufio.NewWriter(w)
+	if err := writeMsgpack(bw, g); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// writeMsgpack writes the generic value g as MessagePack.
+func writeMsgpack(w *bufio.Writer, g interface{}) error {
+	switch v := g.(type) {
+	case nil:
+		return w.WriteByte(0xc0)
+
+	case bool:
+		if v {
+			return w.WriteByte(0xc3)
+		}
+		return w.WriteByte(0xc2)
+
+	case json.Number:
+		return writeMsgpackNumber(w, v)
+
+	case string:
+		writeMsgpackHeader(w, uint64(len(v)), 0xa0, 31, 0xd9, 0xda, 0xdb)
+		_, err := w.WriteString(v)
+		return err
+
+	case []interface{}:
+		writeMsgpackHeader(w, uint64(len(v)), 0x90, 15, 0, 0xdc, 0xdd)
+		for _, e := range v {
+			if err := writeMsgpack(w, e); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case genericMap:
+		writeMsgpackHeader(w, uint64(len(v)), 0x80, 15, 0, 0xde, 0xdf)
+		for _, m := range v {
+			if err := writeMsgpack(w, m.Key); err != nil {
+				return err
+			}
+			if err := writeMsgpack(w, m.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("output: cannot encode %T as MessagePack", g)
+	}
+}
+
+// writeMsgpackHeader writes the header for a string, array, or map of length n. fix
+// is the prefix used for lengths up to fixMax, and the 8, 16, and 32 prefixes are
+// used for larger lengths. A zero prefix means that size is not available for the
+// type.
+func writeMsgpackHeader(w *bufio.Writer, n uint64, fix byte, fixMax uint64, p8, p16, p32 byte) {
+	switch {
+	case n <= fixMax:
+		w.WriteByte(fix | byte(n))
+	case p8 != 0 && n <= math.MaxUint8:
+		w.WriteByte(p8)
+		w.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		w.WriteByte(p16)
+		w.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		w.WriteByte(p32)
+		w.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+// writeMsgpackNumber writes a number using the smallest MessagePack representation.
+// Integers are written as integers, everything else as a float64.
+func writeMsgpackNumber(w *bufio.Writer, n json.Number) (err error) {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		switch {
+		case i >= 0:
+			writeMsgpackUint(w, uint64(i))
+		case i >= -32:
+			w.WriteByte(byte(int8(i)))
+		case i >= math.MinInt8:
+			w.WriteByte(0xd0)
+			w.WriteByte(byte(int8(i)))
+		case i >= math.MinInt16:
+			w.WriteByte(0xd1)
+			w.Write(binary.BigEndian.AppendUint16(nil, uint16(int16(i))))
+		case i >= math.MinInt32:
+			w.WriteByte(0xd2)
+			w.Write(binary.BigEndian.AppendUint32(nil, uint32(int32(i))))
+		default:
+			w.WriteByte(0xd3)
+			w.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+		}
+		return nil
+	}
+
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		writeMsgpackUint(w, u)
+		return nil
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return
+	}
+
+	w.WriteByte(0xcb)
+	_, err = w.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+	return
+}
+
+// writeMsgpackUint writes a non-negative integer.
+func writeMsgpackUint(w *bufio.Writer, u uint64) {
+	switch {
+	case u <= 127:
+		w.WriteByte(byte(u))
+	case u <= math.MaxUint8:
+		w.WriteByte(0xcc)
+		w.WriteByte(byte(u))
+	case u <= math.MaxUint16:
+		w.WriteByte(0xcd)
+		w.Write(binary.BigEndian.AppendUint16(nil, uint16(u)))
+	case u <= math.MaxUint32:
+		w.WriteByte(0xce)
+		w.Write(binary.BigEndian.AppendUint32(nil, uint32(u)))
+	default:
+		w.WriteByte(0xcf)
+		w.Write(binary.BigEndian.AppendUint64(nil, u))
+	}
+}
@@ -0,0 +1,32 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// errMultiple is the error sent by ErrorMulti.
+var errMultiple = errors.New("multiple errors")
+
+// ErrorMulti is used when more than one independent error occured, for example when
+// some items in a batch could not be saved. Each error is listed in the Payload's
+// Errors and ErrorData holds a summary. This returns the same HTTP status as Error.
+func ErrorMulti(errs []ErrorPayload, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorMulti(errs, w, opts...)
+	return
+}
+
+// ErrorMulti sends an error listing multiple errors. See the package-level
+// ErrorMulti.
+func (r *Responder) ErrorMulti(errs []ErrorPayload, w http.ResponseWriter, opts ...SendOption) (err error) {
+	ep := r.newErrorPayload(errMultiple, fmt.Sprintf("%d errors occured.", len(errs)), w)
+
+	r.log(w, "output.ErrorMulti", "count", len(errs), "errors", errs)
+
+	p := r.newPayload(false, TypeError, nil, ep)
+	p.Errors = errs
+
+	err = r.send(&p, w, r.errorCode, opts...)
+	return
+}
@@ -0,0 +1,184 @@
+package output
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// requestWriter is an http.ResponseWriter that also carries the request being
+// responded to. This allows the package's funcs to use details of the request, such
+// as the Accept header, without changing the signature of each func.
+type requestWriter struct {
+	http.ResponseWriter
+	req *http.Request
+
+	//wroteHeader is set once the response code has been written, by this package
+	//or the caller, so that a second response is not sent. See ErrAlreadySent.
+	wroteHeader bool
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (rw *requestWriter) WriteHeader(code int) {
+	//Informational responses, such as 103 Early Hints, can be followed by the
+	//actual response.
+	if code >= http.StatusOK {
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (rw *requestWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter. This is used by
+// http.ResponseController.
+func (rw *requestWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Negotiate returns a ResponseWriter that causes responses sent with it to be
+// tailored to req, most notably encoded based on req's Accept header. Use the
+// returned ResponseWriter with any of the package's funcs or a Responder's methods.
+//
+//	w = output.Negotiate(w, r)
+//	output.DataFound(data, w)
+//
+// The Encoder used is the one, from the Responder's encoders, that best matches the
+// Accept header. If no encoder matches, or the request does not have an Accept
+// header, the Responder's first encoder is used.
+//
+// The request is also used for other request-aware behavior, such as building
+// pagination Link headers (see WithPaginationLinks) and omitting the body of
+// responses to HEAD requests.
+func Negotiate(w http.ResponseWriter, req *http.Request) http.ResponseWriter {
+	return &requestWriter{
+		ResponseWriter: w,
+		req:            req,
+	}
+}
+
+// requestFrom returns the request stored in w by Negotiate, or nil if w does not
+// carry a request.
+func requestFrom(w http.ResponseWriter) *http.Request {
+	rw := unwrapRequestWriter(w)
+	if rw == nil {
+		return nil
+	}
+
+	return rw.req
+}
+
+// unwrapRequestWriter returns the requestWriter created by Negotiate that w is, or
+// wraps, or nil if w does not carry a request. Any wrapping of w, done via an Unwrap
+// method, is walked.
+func unwrapRequestWriter(w http.ResponseWriter) *requestWriter {
+	for w != nil {
+		if rw, ok := w.(*requestWriter); ok {
+			return rw
+		}
+
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil
+		}
+		w = u.Unwrap()
+	}
+
+	return nil
+}
+
+// acceptRange is a media range from an Accept header.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept parses an Accept header into its media ranges. Ranges that cannot be
+// parsed are skipped.
+func parseAccept(header string) (ranges []acceptRange) {
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		mt, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			q, err = strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		ranges = append(ranges, acceptRange{mediaType: mt, q: q})
+	}
+
+	return
+}
+
+// matchRange returns how specifically a media range matches a media type. A higher
+// number is a more specific match, 0 means no match.
+func matchRange(mediaRange, mediaType string) int {
+	if mediaRange == mediaType {
+		return 3
+	}
+
+	if mediaRange == "*/*" {
+		return 1
+	}
+
+	rangeType, rangeSub, _ := strings.Cut(mediaRange, "/")
+	typ, _, _ := strings.Cut(mediaType, "/")
+	if rangeSub == "*" && rangeType == typ {
+		return 2
+	}
+
+	return 0
+}
+
+// negotiate returns the Encoder from encoders that best matches the Accept header.
+// The first encoder is returned if the header is empty or nothing matches.
+func negotiate(accept string, encoders []Encoder) Encoder {
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return encoders[0]
+	}
+
+	var (
+		best  Encoder
+		bestQ float64
+	)
+	for _, enc := range encoders {
+		mt := mediaType(enc)
+
+		//Find the quality for this encoder from the most specific matching range.
+		q, specificity := 0.0, 0
+		for _, ar := range ranges {
+			s := matchRange(ar.mediaType, mt)
+			if s > specificity {
+				q, specificity = ar.q, s
+			}
+		}
+
+		//Earlier encoders win ties since they are preferred.
+		if q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+
+	if best == nil {
+		return encoders[0]
+	}
+
+	return best
+}
@@ -0,0 +1,33 @@
+package output
+
+import "net/http"
+
+// NoContent is used when a request was successful and there is nothing to send
+// back, typically for deletes or fire-and-forget requests. This returns an HTTP
+// status 204 with no body, not even a Payload.
+func NoContent(w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.NoContent(w, opts...)
+	return
+}
+
+// NoContent sends a 204 with no body. See the package-level NoContent.
+func (r *Responder) NoContent(w http.ResponseWriter, opts ...SendOption) (err error) {
+	p := Payload{OK: true}
+	err = r.send(&p, w, http.StatusNoContent, opts...)
+	return
+}
+
+// bodyAllowed reports whether a response with the provided status code can have a
+// body. See RFC 9110.
+func bodyAllowed(responseCode int) bool {
+	switch {
+	case responseCode >= 100 && responseCode <= 199:
+		return false
+	case responseCode == http.StatusNoContent:
+		return false
+	case responseCode == http.StatusNotModified:
+		return false
+	}
+
+	return true
+}
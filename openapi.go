@@ -0,0 +1,122 @@
+package output
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// RegisterDataType registers the type of Data sent with the message type msgType by
+// the default Responder. See Responder.RegisterDataType.
+func RegisterDataType(msgType MessageType, example interface{}) {
+	std.RegisterDataType(msgType, example)
+}
+
+// WithDataType registers the type of Data sent with the message type msgType when
+// creating a Responder. See Responder.RegisterDataType.
+func WithDataType(msgType MessageType, example interface{}) Option {
+	return func(r *Responder) {
+		r.RegisterDataType(msgType, example)
+	}
+}
+
+// RegisterDataType registers the type of Data sent with the message type msgType, as
+// given by example, for use with OpenAPIComponents. The message type is also
+// registered with RegisterMessageTypes. example is typically the zero value of the
+// type.
+//
+//	r.RegisterDataType("userFound", User{})
+//	r.RegisterDataType("usersFound", []User{})
+func (r *Responder) RegisterDataType(msgType MessageType, example interface{}) {
+	r.RegisterMessageTypes(msgType)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.dataTypes == nil {
+		r.dataTypes = make(map[MessageType]reflect.Type)
+	}
+
+	r.dataTypes[msgType] = reflect.TypeOf(example)
+}
+
+// OpenAPIComponents returns the OpenAPI 3.1 components for the Payloads sent by the
+// default Responder. See Responder.OpenAPIComponents.
+func OpenAPIComponents() []byte {
+	return std.OpenAPIComponents()
+}
+
+// OpenAPIComponents returns an OpenAPI 3.1 Components Object describing the
+// Payloads sent by the Responder, so that API documentation stays in sync with what
+// is actually sent. The result is meant to be merged into the "components" of an
+// OpenAPI document.
+//
+// A response is defined for each registered message type, see RegisterMessageTypes
+// and RegisterDataType, named after the message type. Each response is the Payload,
+// as described by Schema, with Type set to the message type and Data described by
+// the type registered with RegisterDataType. The schemas of the Payload, the types
+// it uses, and the registered Data types are defined in "schemas" and referenced by
+// each response.
+//
+//	paths:
+//	  /users/{id}:
+//	    get:
+//	      responses:
+//	        "200":
+//	          $ref: "#/components/responses/userFound"
+func (r *Responder) OpenAPIComponents() []byte {
+	const refPrefix = "#/components/schemas/"
+
+	sb := newSchemaBuilder(r.keys, refPrefix)
+	sb.defTypes["Payload"] = reflect.TypeOf(Payload{})
+	sb.defs["Payload"] = r.envelopeSchema(sb)
+
+	r.mu.RLock()
+	msgTypes := make([]string, 0, len(r.messageTypes))
+	for t := range r.messageTypes {
+		msgTypes = append(msgTypes, string(t))
+	}
+	dataTypes := make(map[MessageType]reflect.Type, len(r.dataTypes))
+	for k, v := range r.dataTypes {
+		dataTypes[k] = v
+	}
+	r.mu.RUnlock()
+
+	sort.Strings(msgTypes)
+
+	responses := make(map[string]interface{}, len(msgTypes))
+	for _, t := range msgTypes {
+		props := map[string]interface{}{
+			sb.keys.key("Type", "Type"): map[string]interface{}{
+				"const": t,
+			},
+		}
+
+		if dt := dataTypes[MessageType(t)]; dt != nil {
+			props[sb.keys.key("Data", "Data")] = sb.typeSchema(dt, false)
+		}
+
+		responses[t] = map[string]interface{}{
+			"description": "A Payload with the " + t + " message type.",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{
+						"allOf": []interface{}{
+							map[string]interface{}{"$ref": refPrefix + "Payload"},
+							map[string]interface{}{"properties": props},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	components := map[string]interface{}{
+		"schemas":   sb.defs,
+		"responses": responses,
+	}
+
+	//This cannot fail since the components only contain maps, slices, and strings.
+	b, _ := json.Marshal(components)
+	return b
+}
@@ -27,26 +27,43 @@ from these functions can usually be ignored; the error is only useful if you are
 defining custom message types, EnforceStrictMessageTypes is enabled, and you used a
 not-previously defined message type in the call to Success or its wrapper functions.
 The error will report that you must use a defined message type.
+
+The package-level funcs use a default configuration. If you need different behavior,
+such as different HTTP status codes or a different logger, or need multiple services
+in one binary to behave differently, create a Responder with New and use its methods
+instead. Responder methods match the package-level funcs.
+
+Each func also accepts SendOptions to alter a single response, such as WithStatusCode
+or WithHeader, for when a response needs to deviate slightly from the norm.
 */
 package output
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
-	"log"
 	"net/http"
-	"strings"
-	"time"
 )
 
+// MessageType is a descriptive title for response data, used to set a Payload's
+// Type. Using a defined type, versus a plain string, allows message types to be
+// declared as constants and shared so that typos are caught at compile time.
+type MessageType string
+
 // Some message types are predefined due to common use.
 const (
-	msgTypeError     = "error"     //used when returning an error with the Error function.
-	msgTypeInsertOK  = "insertOK"  //used when inserting into a database is successful with the InsertOK function.
-	msgTypeUpdateOK  = "updateOK"  //used when updating a database is successful with the UpdateOK function.
-	msgTypeDeleteOK  = "deleteOK"  //used when deleting something in the database is successful with the DeleteOK function.
-	msgTypeDataFound = "dataFound" //used when retrieving data from the database is successful with the DataFound function.
+	TypeError     MessageType = "error"     //used when returning an error with the Error function.
+	TypeInsertOK  MessageType = "insertOK"  //used when inserting into a database is successful with the InsertOK function.
+	TypeUpdateOK  MessageType = "updateOK"  //used when updating a database is successful with the UpdateOK function.
+	TypeDeleteOK  MessageType = "deleteOK"  //used when deleting something in the database is successful with the DeleteOK function.
+	TypeDataFound MessageType = "dataFound" //used when retrieving data from the database is successful with the DataFound function.
+	TypeCreated   MessageType = "created"   //used when a resource was created with the Created function.
+	TypeAccepted  MessageType = "accepted"  //used when a job was accepted for asynchronous processing with the Accepted function.
+	TypeRedirect  MessageType = "redirect"  //used when the client should go to a different URL with the Redirect and RedirectJSON functions.
+
+	TypeMethodNotAllowed MessageType = "methodNotAllowed" //used when the request's HTTP method is not supported with the ErrorMethodNotAllowed function.
+	TypeMaintenance      MessageType = "maintenance"      //used for all responses when maintenance mode is enabled.
+	TypeTimeout          MessageType = "timeout"          //used when handling a request took too long with the ErrorTimeout function.
+	TypePanic            MessageType = "panic"            //used when a panic was recovered by the RecoverMiddleware function.
+	TypeResponseTooLarge MessageType = "responseTooLarge" //used when a response is larger than the limit set with WithMaxResponseSize.
 )
 
 // Define errors returned in HTTP responses.
@@ -61,6 +78,15 @@ var (
 	//ErrInvalidResponseCode is returned when a non-existant HTTP status code is
 	//provided.
 	ErrInvalidResponseCode = errors.New("output: invalid HTTP response code")
+
+	//ErrUndefinedMessageType is returned when strict message types are enforced on
+	//a Responder and a message type that was not defined is used.
+	ErrUndefinedMessageType = errors.New("output: undefined message type, you must use a defined message type")
+
+	//ErrBodyNotAllowed is returned when Data is provided with an HTTP status code
+	//that cannot have a body, such as 204. The response is still sent, without the
+	//Data.
+	ErrBodyNotAllowed = errors.New("output: response code does not allow a body, data not sent")
 )
 
 // Payload is the format of the data that will be sent back to the requestor client.
@@ -93,132 +119,142 @@ type Payload struct {
 	//This field is only populated when OK is false.
 	ErrorData ErrorPayload `json:",omitempty"`
 
+	//Errors is used when more than one independent error occured, for example when
+	//handling a batch of items. ErrorData holds a summary of the errors. See
+	//ErrorMulti.
+	//
+	//This field is only populated when OK is false.
+	Errors []ErrorPayload `json:",omitempty"`
+
+	//Links are hypermedia links for the response's resource. See WithLinks.
+	Links *Links `json:",omitempty"`
+
+	//Warnings lists non-fatal issues with the request, such as ignored parameters,
+	//so a request can succeed while still informing the client. See WithWarning.
+	Warnings []Warning `json:",omitempty"`
+
+	//Meta is metadata about the response, versus the Data itself, such as
+	//pagination details or rate limits. This is the place for cross-cutting
+	//metadata instead of including it in Data. Well-known keys, such as
+	//MetaPagination, are used for metadata set by this package. See WithMeta and
+	//WithDefaultMeta.
+	Meta map[string]interface{} `json:",omitempty"`
+
+	//RequestID is the ID assigned to the request by RequestIDMiddleware. Clients
+	//can quote this in support tickets so the request can be found in logs.
+	RequestID string `json:",omitempty"`
+
+	//TraceID and SpanID identify the trace and span the response was sent in so
+	//that the response can be correlated with the trace. See WithTracer.
+	TraceID string `json:",omitempty"`
+	SpanID  string `json:",omitempty"`
+
+	//DurationMS is the number of milliseconds spent handling the request, so that
+	//clients and dashboards can see server-side processing time. See
+	//TimingMiddleware.
+	DurationMS float64 `json:",omitempty"`
+
+	//APIVersion is the version of the API that sent the response. See
+	//WithAPIVersion.
+	APIVersion string `json:",omitempty"`
+
+	//Sequence is a number that increases with each response sent by a Responder so
+	//that clients can detect missed or out-of-order responses. See WithSequence.
+	Sequence uint64 `json:",omitempty"`
+
 	//Datetime is simply a timestamp of when a mesage was created. This is typically
-	//used for diagnostics on the client side. It is YYYY-MM-DD HH:MM:SS.sss
-	//formatted in the UTC timezone.
-	Datetime string
+	//used for diagnostics on the client side. By default it is RFC 3339 formatted,
+	//with millisecond precision, in the UTC timezone. See WithTimestampFormat.
+	//
+	//This is omitted if empty, see WithOmitDatetime.
+	Datetime string `json:",omitempty"`
+
+	//keys describes how the Payload's keys are encoded, see WithKeyCase.
+	keys *envelopeKeys
 }
 
 // ErrorPayload is descriptive data about an error.
 type ErrorPayload struct {
+	//Code is a stable, machine-readable, identifier for the error. Clients should
+	//use this to handle specific errors instead of matching against Error, which
+	//may be reworded. See RegisterErrorCode.
+	Code string `json:",omitempty"`
+
 	//Error is a lower-level error, typically an err returned from a func.
 	Error string `json:",omitempty"`
 
 	//Message is a higher-level, more human-friendly, message that can be displayed
 	//in a GUI and explains how to resolve the error.
 	Message string `json:",omitempty"`
-}
-
-// buildAndSend builds a Payload from the provided ok, msgType, msgData, and errData
-// and then calls send().
-func buildAndSend(ok bool, msgType string, msgData interface{}, errData ErrorPayload, w http.ResponseWriter, responseCode int) (err error) {
-	//Get timestamp for response. This is used for diagnostics. The "Z" is appended
-	//to the end to signify the datetime is in the UTC timezone.
-	t := time.Now().UTC().Format("2006-01-02T15:04:05.000") + "Z"
 
-	//Build data object being returned.
-	//
-	//Note that Data or ErrorData will be removed from JSON if they are empty (per
-	//struct tags on fields).
-	p := Payload{
-		OK:        ok,
-		Type:      msgType,
-		Data:      msgData,
-		ErrorData: errData,
-		Datetime:  t,
-	}
-
-	//Send the response.
-	err = p.send(w, responseCode)
-	return
+	//Fields lists the individual fields that failed validation. This allows a GUI
+	//to highlight each invalid field instead of parsing Message. See
+	//ErrorValidation.
+	Fields []FieldError `json:",omitempty"`
+
+	//Details is structured context about the error, for example the ID of
+	//conflicting data or the allowed values of a field. See ErrorWithDetails.
+	Details map[string]interface{} `json:",omitempty"`
+
+	//DocsURL links to documentation about the error, for example an entry in a
+	//developer portal. See RegisterError.
+	DocsURL string `json:",omitempty"`
+
+	//Retryable reports if the client may retry the request, unchanged, and expect
+	//it to possibly succeed. This allows client SDKs to implement retry policies
+	//driven by the server instead of guessing based on the HTTP status code. See
+	//WithRetryable.
+	Retryable bool `json:",omitempty"`
+
+	//RetryAfterSeconds is the number of seconds the client should wait before
+	//retrying the request. This is set using WithRetryAfter.
+	RetryAfterSeconds int `json:",omitempty"`
+
+	//Chain is the message of the error and each error it wraps, in order, which
+	//helps with triaging failures that pass through multiple layers. This is only
+	//populated when debug or verbose errors are enabled, see WithVerboseErrors.
+	Chain []string `json:",omitempty"`
+
+	//Stack is a trimmed stack trace showing where the error response was sent
+	//from. This is only populated when debug and stack traces are enabled, see
+	//WithStackTraces.
+	Stack []string `json:",omitempty"`
 }
 
-// send handles actually sending the response.
-func (p *Payload) send(w http.ResponseWriter, responseCode int) (err error) {
-	//Set the response code.
-	w.WriteHeader(responseCode)
-
-	//Set the content type.
-	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-
-	//Send back the JSON response.
-	j, err := json.Marshal(p)
-	w.Write(j)
-	return
+// IsZero reports whether no error data was provided.
+func (ep ErrorPayload) IsZero() bool {
+	return ep.Code == "" && ep.Error == "" && ep.Message == "" && len(ep.Fields) == 0 && len(ep.Details) == 0 && ep.DocsURL == "" && !ep.Retryable && ep.RetryAfterSeconds == 0 && len(ep.Chain) == 0 && len(ep.Stack) == 0
 }
 
 // Send is used to send any response, with any payload, and any response code. This
 // is meant to be used in situations where the Success and Error (and related helper
 // funcs) do not provide enough control over the response, specifically when you want
 // to use non-200 and -500 HTTP status codes.
-func Send(p Payload, w http.ResponseWriter, responseCode int) (err error) {
-	//Do some validation since the payload is constructed manually.
-	if strings.TrimSpace(p.Datetime) == "" {
-		p.Datetime = time.Now().UTC().Format("2006-01-02T15:04:05.000") + "Z"
-	}
-
-	//If ErrorData is provided, OK must be false. Data can still be provided when
-	//errors occur though (see ErrorWithID()).
-	if p.ErrorData != (ErrorPayload{}) {
-		p.OK = false
-	}
-
-	//Make sure a response code was provided.
-	if responseCode < http.StatusContinue {
-		if debug {
-			log.Println("output.Send", "invalid HTTP response code provided", responseCode)
-		}
-
-		err = ErrInvalidResponseCode
-		return
-	}
-
-	//Make sure a message type was provided. Message type is used to handle responses
-	//by the client so this field is required.
-	//
-	//If a type wasn't provided, use the HTTP status code. The code and status
-	//message are concatted together so hopefully help developers identify that they
-	//did not provide a status code manually.
-	if strings.TrimSpace(p.Type) == "" {
-		p.Type = fmt.Sprintf("%d-%s", responseCode, http.StatusText(responseCode))
-
-		if debug {
-			log.Println("output.Send", "payload has not message type, defaulting to type based on HTTP response code.", responseCode, p.Type)
-		}
-	}
-
-	//We could do some checking if a 4xx code was provided if ErrorData was also
-	//provided, but we would rather not enforce a set of status codes since each
-	//application that uses this package may have different ideas of how to use
-	//ErrorData and what applicable code to return.
-
-	//Send the response.
-	err = p.send(w, responseCode)
+func Send(p Payload, w http.ResponseWriter, responseCode int, opts ...SendOption) (err error) {
+	err = std.Send(p, w, responseCode, opts...)
 	return
 }
 
-// debug is used to enable diagnostic logging.
-var debug = false
-
-// Debug turns debug logging on or off.
+// Debug turns debug logging on or off for the default Responder.
 func Debug(b bool) {
-	debug = b
+	std.Debug(b)
 }
 
 // Success is used when a request was successful and one of the other successful
 // response funcs (InsertOK, UpdateOK, DataFound, etc.) doesn't fit. While an error
 // is returned, it is typically ignored.
 //
-// Success, and related functions, always returns an HTTP status 200.
-func Success(msgType string, data interface{}, w http.ResponseWriter) (err error) {
-	err = buildAndSend(true, msgType, data, ErrorPayload{}, w, http.StatusOK)
+// Success, and related functions, returns an HTTP status 200 unless the default
+// Responder was configured otherwise.
+func Success(msgType MessageType, data interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.Success(msgType, data, w, opts...)
 	return
 }
 
 // InsertOK is used when a request resulted in data being successfully inserted into
 // a database. This allows for sending by the just inserted data's ID.
-func InsertOK(id int64, w http.ResponseWriter) (err error) {
-	err = Success(msgTypeInsertOK, id, w)
+func InsertOK(id int64, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.InsertOK(id, w, opts...)
 	return
 }
 
@@ -226,62 +262,52 @@ func InsertOK(id int64, w http.ResponseWriter) (err error) {
 // inserted into a database and you want to send back a bunch of data with the
 // response. While InsertOK can only send back an integer ID, this can send back
 // anything.
-func InsertOKWithData(data interface{}, w http.ResponseWriter) (err error) {
-	err = Success(msgTypeInsertOK, data, w)
+func InsertOKWithData(data interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.InsertOKWithData(data, w, opts...)
 	return
 }
 
 // UpdateOK is used when a request resulted in data being successfully updated in a
 // database.
-func UpdateOK(w http.ResponseWriter) (err error) {
-	err = Success(msgTypeUpdateOK, nil, w)
+func UpdateOK(w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.UpdateOK(w, opts...)
 	return
 }
 
 // UpdateOKWithData is used when a request resulted in data being successfully
 // updated in a database and you want to send back a bunch of data with the response.
-func UpdateOKWithData(data interface{}, w http.ResponseWriter) (err error) {
-	err = Success(msgTypeUpdateOK, data, w)
+func UpdateOKWithData(data interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.UpdateOKWithData(data, w, opts...)
 	return
 }
 
 // DataFound is used to send back data in a response. This is typically used with
 // looking up data from a database.
-func DataFound(data interface{}, w http.ResponseWriter) (err error) {
-	err = Success(msgTypeDataFound, data, w)
+func DataFound(data interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.DataFound(data, w, opts...)
 	return
 }
 
 // Error is used when an error occured with a request and one of the other error
 // response funcs (ErrorInputInvalid, etc.) doesn't fit.
 //
-// Error, and related functions, always returns an HTTP status 500.
-func Error(errType error, errMsg string, w http.ResponseWriter) (err error) {
-	//Define the error related data.
-	ep := ErrorPayload{
-		Error:   errType.Error(),
-		Message: errMsg,
-	}
-
-	//Logging of errors can be used for diagnostics.
-	if debug {
-		log.Println("output.Error", errType, errMsg)
-	}
-
-	err = buildAndSend(false, msgTypeError, nil, ep, w, http.StatusInternalServerError)
+// Error, and related functions, returns an HTTP status 500 unless the default
+// Responder was configured otherwise.
+func Error(errType error, errMsg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.Error(errType, errMsg, w, opts...)
 	return
 }
 
 // ErrorInputInvalid is used when an error occurs while performing input validation.
-func ErrorInputInvalid(msg string, w http.ResponseWriter) (err error) {
-	err = Error(errInputInvalid, msg, w)
+func ErrorInputInvalid(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorInputInvalid(msg, w, opts...)
 	return
 }
 
 // ErrorAlreadyExists is used when trying to insert something into the db that already
 // exists.
-func ErrorAlreadyExists(msg string, w http.ResponseWriter) (err error) {
-	err = Error(errAlreadyExists, msg, w)
+func ErrorAlreadyExists(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorAlreadyExists(msg, w, opts...)
 	return
 }
 
@@ -289,17 +315,8 @@ func ErrorAlreadyExists(msg string, w http.ResponseWriter) (err error) {
 // This is used when you saved some data to a database and you want subsequent
 // request to "retry" using the existing ID instead of recreating records over an
 // over with each error.
-func ErrorWithID(errType error, errMsg string, id int64, w http.ResponseWriter) (err error) {
-	ep := ErrorPayload{
-		Error:   errType.Error(),
-		Message: errMsg,
-	}
-
-	if debug {
-		log.Println("output.ErrorWithID", errType, errMsg, id)
-	}
-
-	err = buildAndSend(false, msgTypeError, id, ep, w, http.StatusInternalServerError)
+func ErrorWithID(errType error, errMsg string, id int64, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorWithID(errType, errMsg, id, w, opts...)
 	return
 }
 
@@ -307,7 +324,7 @@ func ErrorWithID(errType error, errMsg string, id int64, w http.ResponseWriter)
 // an I when an input validation error occured. This is used when you saved some data
 // to a database and you want subsequent requests to "retry" using the existing ID
 // instead of recreating records over an over with each error.
-func ErrorInputInvalidWithID(msg string, id int64, w http.ResponseWriter) (err error) {
-	err = ErrorWithID(errInputInvalid, msg, id, w)
+func ErrorInputInvalidWithID(msg string, id int64, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorInputInvalidWithID(msg, id, w, opts...)
 	return
 }
@@ -0,0 +1,54 @@
+/*
+Package outputamqp publishes output Payloads to an AMQP broker, such as RabbitMQ, so
+that asynchronous consumers receive the same Payload that HTTP clients do.
+
+Use a Publisher with a Responder:
+
+	ch, err := conn.Channel()
+	if err != nil {
+		log.Fatal(err)
+	}
+	pub := outputamqp.New(ch, "events", "orders.")
+
+	err = r.Publish(ctx, pub, output.Payload{OK: true, Type: "orderShipped", Data: order})
+
+Each Payload is published to the exchange with a routing key of the prefix followed
+by the Payload's Type, "orders.orderShipped" above.
+*/
+package outputamqp
+
+import (
+	"context"
+	"time"
+
+	"github.com/c9845/output"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Publisher is an output.Publisher that publishes Payloads to an AMQP exchange.
+type Publisher struct {
+	ch               *amqp.Channel
+	exchange         string
+	routingKeyPrefix string
+}
+
+// New returns a Publisher that publishes Payloads to exchange using ch.
+// routingKeyPrefix is prepended to each Payload's Type to get the routing key.
+func New(ch *amqp.Channel, exchange, routingKeyPrefix string) *Publisher {
+	return &Publisher{
+		ch:               ch,
+		exchange:         exchange,
+		routingKeyPrefix: routingKeyPrefix,
+	}
+}
+
+// Publish implements output.Publisher.
+func (p *Publisher) Publish(ctx context.Context, m output.Message) error {
+	return p.ch.PublishWithContext(ctx, p.exchange, p.routingKeyPrefix+m.Type, false, false, amqp.Publishing{
+		ContentType:  m.ContentType,
+		Type:         m.Type,
+		Timestamp:    time.Now().UTC(),
+		DeliveryMode: amqp.Persistent,
+		Body:         m.Body,
+	})
+}
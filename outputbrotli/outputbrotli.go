@@ -0,0 +1,64 @@
+/*
+Package outputbrotli compresses output responses with brotli, which typically
+produces smaller responses than gzip and is supported by all modern browsers.
+
+Use Compressor with a Responder that has compression enabled:
+
+	r := output.New(
+		output.WithCompression(0),
+		output.WithCompressors(outputbrotli.Compressor, output.Gzip),
+	)
+*/
+package outputbrotli
+
+import (
+	"io"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/c9845/output"
+)
+
+// DefaultLevel is the compression level used by Compressor. This is lower than
+// brotli's default since responses are compressed as they are sent, where speed
+// matters more than for static files.
+const DefaultLevel = 5
+
+// Compressor is an output.Compressor for the br content coding using DefaultLevel.
+var Compressor = New(DefaultLevel)
+
+// compressor implements output.Compressor using brotli.
+type compressor struct {
+	writers sync.Pool
+}
+
+// New returns an output.Compressor for the br content coding using level, from
+// brotli.BestSpeed to brotli.BestCompression.
+func New(level int) output.Compressor {
+	c := &compressor{}
+	c.writers.New = func() interface{} {
+		return brotli.NewWriterLevel(nil, level)
+	}
+
+	return c
+}
+
+// Encoding implements output.Compressor.
+func (c *compressor) Encoding() string {
+	return "br"
+}
+
+// Compress implements output.Compressor.
+func (c *compressor) Compress(dst io.Writer, src []byte) (err error) {
+	bw := c.writers.Get().(*brotli.Writer)
+	defer c.writers.Put(bw)
+	bw.Reset(dst)
+
+	_, err = bw.Write(src)
+	if err != nil {
+		return
+	}
+
+	err = bw.Close()
+	return
+}
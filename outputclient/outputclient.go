@@ -0,0 +1,402 @@
+/*
+Package outputclient is used by Go services to consume responses sent by another
+service using the output package, so that each service does not need to
+re-implement parsing of the Payload.
+
+Use Do in place of http.Client.Do:
+
+	p, err := outputclient.Do(req)
+	if errors.Is(err, outputclient.ErrNotFound) {
+		//handle the missing resource.
+	} else if err != nil {
+		return err
+	}
+
+	var u User
+	err = p.DecodeData(&u)
+
+Responses with OK set to false are returned as an *Error which wraps a sentinel
+error, such as ErrNotFound, based on the ErrorPayload's Code so that errors can be
+checked with errors.Is. Register the codes of errors defined by your services with
+RegisterErrorCode.
+
+Payloads encoded with any KeyCase are decoded, but Payloads encoded with custom
+field names, see output.WithFieldNames, are not.
+*/
+package outputclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/c9845/output"
+)
+
+// Errors matching the codes of the errors defined in the output package. An *Error
+// returned by Do wraps one of these when the response's ErrorPayload has a
+// matching Code.
+var (
+	ErrInputInvalid       = errors.New("input validation error")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrBadRequest         = errors.New("bad request")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrUnprocessable      = errors.New("unprocessable")
+	ErrMethodNotAllowed   = errors.New("method not allowed")
+	ErrServiceUnavailable = errors.New("service unavailable")
+	ErrMaintenance        = errors.New("maintenance")
+	ErrTimeout            = errors.New("timeout")
+	ErrValidation         = errors.New("validation error")
+	ErrMultiple           = errors.New("multiple errors")
+	ErrPanic              = errors.New("panic")
+	ErrResponseTooLarge   = errors.New("response too large")
+	ErrSchemaMismatch     = errors.New("data does not match schema")
+)
+
+// ErrInvalidResponse is returned when a response's body is not a Payload, for
+// example an HTML error page sent by a proxy.
+var ErrInvalidResponse = errors.New("outputclient: response is not a valid payload")
+
+// predefinedCodes maps the codes of the errors defined in the output package to
+// the matching error. These are registered on every Client.
+var predefinedCodes = map[string]error{
+	"inputInvalid":       ErrInputInvalid,
+	"alreadyExists":      ErrAlreadyExists,
+	"badRequest":         ErrBadRequest,
+	"unauthorized":       ErrUnauthorized,
+	"forbidden":          ErrForbidden,
+	"notFound":           ErrNotFound,
+	"conflict":           ErrConflict,
+	"unprocessable":      ErrUnprocessable,
+	"methodNotAllowed":   ErrMethodNotAllowed,
+	"serviceUnavailable": ErrServiceUnavailable,
+	"maintenance":        ErrMaintenance,
+	"timeout":            ErrTimeout,
+	"validation":         ErrValidation,
+	"multiple":           ErrMultiple,
+	"panic":              ErrPanic,
+	"responseTooLarge":   ErrResponseTooLarge,
+	"schemaMismatch":     ErrSchemaMismatch,
+}
+
+// statusErrors maps HTTP status codes to errors. This is used when a response's
+// ErrorPayload has no Code, or a Code that is not registered.
+var statusErrors = map[int]error{
+	http.StatusBadRequest:          ErrBadRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusMethodNotAllowed:    ErrMethodNotAllowed,
+	http.StatusConflict:            ErrConflict,
+	http.StatusUnprocessableEntity: ErrUnprocessable,
+	http.StatusServiceUnavailable:  ErrServiceUnavailable,
+	http.StatusGatewayTimeout:      ErrTimeout,
+}
+
+// Client sends requests and decodes the Payloads sent in response.
+type Client struct {
+	//httpClient sends requests.
+	httpClient *http.Client
+
+	//codes maps error codes to errors, see RegisterErrorCode.
+	codes map[string]error
+
+	mu sync.RWMutex
+}
+
+// Option is used to configure a Client.
+type Option func(*Client)
+
+// std is the Client used by the package-level funcs.
+var std = New()
+
+// New returns a Client configured with opts.
+func New(opts ...Option) *Client {
+	c := &Client{
+		httpClient: http.DefaultClient,
+		codes:      make(map[string]error, len(predefinedCodes)),
+	}
+
+	for code, err := range predefinedCodes {
+		c.codes[code] = err
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithHTTPClient sets the http.Client used to send requests. The default is
+// http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = hc
+		}
+	}
+}
+
+// WithErrorCode registers an error code when creating a Client. See
+// Client.RegisterErrorCode.
+func WithErrorCode(code string, target error) Option {
+	return func(c *Client) {
+		c.RegisterErrorCode(code, target)
+	}
+}
+
+// RegisterErrorCode registers the error wrapped by an *Error when a response's
+// ErrorPayload has the given code with the default Client. See
+// Client.RegisterErrorCode.
+func RegisterErrorCode(code string, target error) {
+	std.RegisterErrorCode(code, target)
+}
+
+// RegisterErrorCode registers the error wrapped by an *Error when a response's
+// ErrorPayload has the given code. This is the client side of
+// output.RegisterErrorCode, so the same code should be used on both sides.
+//
+//	outputclient.RegisterErrorCode("cardDeclined", ErrCardDeclined)
+func (c *Client) RegisterErrorCode(code string, target error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.codes[code] = target
+}
+
+// Payload is a Payload decoded from a response.
+type Payload struct {
+	output.Payload
+
+	//StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	//Header is the response's headers.
+	Header http.Header
+
+	//data is the encoded Data, see DecodeData.
+	data json.RawMessage
+}
+
+// DecodeData decodes the Payload's Data into v, which must be a pointer, like with
+// json.Unmarshal. Nothing is done if the Payload has no Data.
+func (p *Payload) DecodeData(v interface{}) (err error) {
+	if len(p.data) == 0 {
+		return
+	}
+
+	err = json.Unmarshal(p.data, v)
+	return
+}
+
+// Error is returned by Do when a response's Payload has OK set to false.
+type Error struct {
+	//StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	//Type is the Payload's Type.
+	Type string
+
+	//ErrorData is the Payload's ErrorData.
+	ErrorData output.ErrorPayload
+
+	//Errors is the Payload's Errors, see output.ErrorMulti.
+	Errors []output.ErrorPayload
+
+	//RequestID is the Payload's RequestID.
+	RequestID string
+
+	//err is the error matching the ErrorData's Code, the Type, or the status code.
+	err error
+}
+
+// Error returns the ErrorData's Message, or Error if no Message was provided,
+// along with the Code and status code.
+func (e *Error) Error() string {
+	msg := e.ErrorData.Message
+	if msg == "" {
+		msg = e.ErrorData.Error
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+
+	if e.ErrorData.Code != "" {
+		msg = e.ErrorData.Code + ": " + msg
+	}
+
+	return "outputclient: " + msg + " (" + strconv.Itoa(e.StatusCode) + ")"
+}
+
+// Unwrap returns the error matching the ErrorData's Code, such as ErrNotFound, so
+// that errors.Is can be used.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Do sends req using the default Client. See Client.Do.
+func Do(req *http.Request) (p *Payload, err error) {
+	p, err = std.Do(req)
+	return
+}
+
+// Do sends req and decodes the Payload sent in response. The Accept header is set
+// to JSON if it was not set.
+//
+// If the Payload's OK is false, the Payload is returned along with an *Error. An
+// error wrapping ErrInvalidResponse is returned if the response's body is not a
+// Payload.
+func (c *Client) Do(req *http.Request) (p *Payload, err error) {
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	p, err = c.Decode(resp)
+	return
+}
+
+// Decode decodes the Payload from resp using the default Client. See
+// Client.Decode.
+func Decode(resp *http.Response) (p *Payload, err error) {
+	p, err = std.Decode(resp)
+	return
+}
+
+// Decode decodes the Payload from resp's body, for when a request is sent some
+// other way than with Do. The body is read but not closed. Errors are returned as
+// with Do.
+func (c *Client) Decode(resp *http.Response) (p *Payload, err error) {
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	p = &Payload{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}
+
+	//A response without a body, such as a 204, has no Payload.
+	if len(bytes.TrimSpace(b)) == 0 {
+		p.OK = resp.StatusCode < http.StatusBadRequest
+		if !p.OK {
+			err = c.newError(p)
+		}
+		return
+	}
+
+	b, err = normalizeKeys(b)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+		return
+	}
+
+	var raw struct {
+		Data json.RawMessage
+	}
+	if err = json.Unmarshal(b, &p.Payload); err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+		return
+	}
+	if err = json.Unmarshal(b, &raw); err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+		return
+	}
+	p.data = raw.Data
+
+	if !p.OK {
+		err = c.newError(p)
+	}
+
+	return
+}
+
+// newError returns the *Error for a Payload with OK set to false.
+func (c *Client) newError(p *Payload) error {
+	e := &Error{
+		StatusCode: p.StatusCode,
+		Type:       p.Type,
+		ErrorData:  p.ErrorData,
+		Errors:     p.Errors,
+		RequestID:  p.RequestID,
+	}
+
+	c.mu.RLock()
+	e.err = c.codes[p.ErrorData.Code]
+	if e.err == nil {
+		e.err = c.codes[p.Type]
+	}
+	c.mu.RUnlock()
+
+	if e.err == nil {
+		e.err = statusErrors[p.StatusCode]
+	}
+
+	return e
+}
+
+// normalizeKeys removes underscores from the keys of the Payload, and the keys of
+// data defined in the output package such as ErrorData, so that Payloads encoded
+// with KeyCaseSnake are decoded. Keys are otherwise matched case-insensitively by
+// json.Unmarshal. The keys of Data, Meta, and Details are not changed since they are
+// user-defined.
+func normalizeKeys(b []byte) ([]byte, error) {
+	if !bytes.Contains(b, []byte("_")) {
+		return b, nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(stripUnderscores(v))
+}
+
+// stripUnderscores removes underscores from the keys of each object in v, except
+// within user-defined fields.
+func stripUnderscores(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			k = strings.ReplaceAll(k, "_", "")
+
+			switch strings.ToLower(k) {
+			case "data", "meta", "details":
+				m[k] = e
+			default:
+				m[k] = stripUnderscores(e)
+			}
+		}
+		return m
+
+	case []interface{}:
+		for i := range t {
+			t[i] = stripUnderscores(t[i])
+		}
+		return t
+
+	default:
+		return v
+	}
+}
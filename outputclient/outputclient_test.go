@@ -0,0 +1,146 @@
+package outputclient
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/c9845/output"
+)
+
+func TestPredefinedCodes(t *testing.T) {
+	//Every Code sent by the output package must be matched, and no Code that is not
+	//sent should be, so that the two packages do not drift apart.
+	codes := output.PredefinedErrorCodes()
+	for _, code := range codes {
+		if _, ok := predefinedCodes[code]; !ok {
+			t.Errorf("code %q is not in predefinedCodes", code)
+		}
+	}
+	if len(codes) != len(predefinedCodes) {
+		t.Errorf("got %d predefinedCodes, want %d", len(predefinedCodes), len(codes))
+	}
+}
+
+func TestNormalizeKeys(t *testing.T) {
+	sent := output.Payload{
+		OK:   false,
+		Type: "error",
+		Data: map[string]interface{}{"user_id": "1"},
+		ErrorData: output.ErrorPayload{
+			Code:              "notFound",
+			Error:             "not found",
+			Message:           "The user was not found.",
+			Fields:            []output.FieldError{{Field: "first_name", Rule: "required", Message: "Required."}},
+			Details:           map[string]interface{}{"user_id": "1"},
+			DocsURL:           "https://example.com/errors/notFound",
+			Retryable:         true,
+			RetryAfterSeconds: 5,
+		},
+		Warnings:   []output.Warning{{Code: "deprecated", Message: "Deprecated."}},
+		Meta:       map[string]interface{}{"rate_limit": "10"},
+		RequestID:  "req1",
+		TraceID:    "trace1",
+		SpanID:     "span1",
+		DurationMS: 1.5,
+		APIVersion: "v1",
+		Datetime:   "2024-01-02T03:04:05.000Z",
+	}
+
+	tests := []struct {
+		name    string
+		keyCase output.KeyCase
+	}{
+		{"default", output.KeyCaseDefault},
+		{"camel", output.KeyCaseCamel},
+		{"snake", output.KeyCaseSnake},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := output.New(output.WithKeyCase(tt.keyCase))
+
+			w := httptest.NewRecorder()
+			if err := r.Send(sent, w, 404); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Decode(w.Result())
+			var e *Error
+			if !errors.As(err, &e) || !errors.Is(err, ErrNotFound) {
+				t.Fatalf("got error %v, want ErrNotFound", err)
+			}
+
+			//Data, Meta, and Details are user-defined, so their keys are not changed.
+			want := sent
+			want.Data = map[string]interface{}{"user_id": "1"}
+			if !reflect.DeepEqual(got.Payload.ErrorData, want.ErrorData) {
+				t.Fatalf("got ErrorData %+v, want %+v", got.Payload.ErrorData, want.ErrorData)
+			}
+			if !reflect.DeepEqual(got.Payload.Meta, want.Meta) {
+				t.Fatalf("got Meta %+v, want %+v", got.Payload.Meta, want.Meta)
+			}
+			if !reflect.DeepEqual(got.Payload.Warnings, want.Warnings) {
+				t.Fatalf("got Warnings %+v, want %+v", got.Payload.Warnings, want.Warnings)
+			}
+
+			var data map[string]interface{}
+			if err := got.DecodeData(&data); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(data, want.Data) {
+				t.Fatalf("got Data %+v, want %+v", data, want.Data)
+			}
+
+			got.Payload.Data = nil
+			got.Payload.ErrorData = output.ErrorPayload{}
+			got.Payload.Meta = nil
+			got.Payload.Warnings = nil
+			want.Data = nil
+			want.ErrorData = output.ErrorPayload{}
+			want.Meta = nil
+			want.Warnings = nil
+			if !reflect.DeepEqual(got.Payload, want) {
+				t.Fatalf("got %+v, want %+v", got.Payload, want)
+			}
+		})
+	}
+}
+
+func TestStripUnderscores(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		want interface{}
+	}{
+		{
+			name: "envelope keys",
+			in:   map[string]interface{}{"request_id": "1", "error_data": map[string]interface{}{"retry_after_seconds": 1}},
+			want: map[string]interface{}{"requestid": "1", "errordata": map[string]interface{}{"retryafterseconds": 1}},
+		},
+		{
+			name: "user-defined keys",
+			in:   map[string]interface{}{"data": map[string]interface{}{"user_id": 1}, "meta": map[string]interface{}{"rate_limit": 1}},
+			want: map[string]interface{}{"data": map[string]interface{}{"user_id": 1}, "meta": map[string]interface{}{"rate_limit": 1}},
+		},
+		{
+			name: "arrays",
+			in:   []interface{}{map[string]interface{}{"field_name": "a"}},
+			want: []interface{}{map[string]interface{}{"fieldname": "a"}},
+		},
+		{
+			name: "scalar",
+			in:   "a_b",
+			want: "a_b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripUnderscores(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Package outputfasthttp allows output to send responses from fasthttp handlers.
+
+output writes responses to an http.ResponseWriter, which is only used to set
+headers, write the status code, and write the body. NewResponseWriter adapts a
+fasthttp.RequestCtx to this small interface:
+
+	func handler(ctx *fasthttp.RequestCtx) {
+		w := outputfasthttp.NewResponseWriter(ctx)
+		output.DataFound(data, w)
+	}
+
+The returned ResponseWriter also carries the request, as with output.Negotiate, so
+request-aware behavior such as content negotiation, omitting the body of responses
+to HEAD requests, ETags and 304 responses, and compression works as it does with
+net/http.
+
+Streaming responses, such as output.EventStream, are not supported since fasthttp
+buffers the response body.
+*/
+package outputfasthttp
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/c9845/output"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// responseWriter is an http.ResponseWriter that writes to a fasthttp.RequestCtx.
+type responseWriter struct {
+	ctx         *fasthttp.RequestCtx
+	header      http.Header
+	wroteHeader bool
+}
+
+// NewResponseWriter returns an http.ResponseWriter that writes the response to ctx.
+func NewResponseWriter(ctx *fasthttp.RequestCtx) http.ResponseWriter {
+	w := &responseWriter{
+		ctx:    ctx,
+		header: http.Header{},
+	}
+
+	return output.Negotiate(w, newRequest(ctx))
+}
+
+// newRequest returns the request of ctx as an *http.Request. If the request cannot
+// be fully converted, for example if its URL is invalid, the method, path, and
+// headers are still provided so that request-aware behavior works.
+func newRequest(ctx *fasthttp.RequestCtx) *http.Request {
+	var req http.Request
+	if err := fasthttpadaptor.ConvertRequest(ctx, &req, true); err != nil {
+		req = http.Request{
+			Method: string(ctx.Method()),
+			URL: &url.URL{
+				Path:     string(ctx.Path()),
+				RawQuery: string(ctx.QueryArgs().QueryString()),
+			},
+			Host:       string(ctx.Host()),
+			RemoteAddr: ctx.RemoteAddr().String(),
+			Header:     http.Header{},
+		}
+
+		ctx.Request.Header.VisitAll(func(k, v []byte) {
+			req.Header.Add(string(k), string(v))
+		})
+	}
+
+	return &req
+}
+
+// Header implements http.ResponseWriter.
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader implements http.ResponseWriter. The headers are copied to the
+// fasthttp response since they cannot be changed once the status code is written.
+func (w *responseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	for k, values := range w.header {
+		for i, v := range values {
+			if i == 0 {
+				w.ctx.Response.Header.Set(k, v)
+			} else {
+				w.ctx.Response.Header.Add(k, v)
+			}
+		}
+	}
+
+	w.ctx.SetStatusCode(code)
+}
+
+// Write implements http.ResponseWriter.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ctx.Write(b)
+}
@@ -0,0 +1,106 @@
+package outputfasthttp
+
+import (
+	"testing"
+
+	"github.com/c9845/output"
+	"github.com/valyala/fasthttp"
+)
+
+func TestNewResponseWriter(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		header          map[string]string
+		wantCode        int
+		wantContentType string
+		wantEncoding    string
+		wantBody        bool
+	}{
+		{
+			name:            "get",
+			method:          fasthttp.MethodGet,
+			wantCode:        fasthttp.StatusOK,
+			wantContentType: "application/json; charset=UTF-8",
+			wantBody:        true,
+		},
+		{
+			name:            "negotiate",
+			method:          fasthttp.MethodGet,
+			header:          map[string]string{"Accept": "application/xml"},
+			wantCode:        fasthttp.StatusOK,
+			wantContentType: "application/xml; charset=UTF-8",
+			wantBody:        true,
+		},
+		{
+			name:            "head",
+			method:          fasthttp.MethodHead,
+			wantCode:        fasthttp.StatusOK,
+			wantContentType: "application/json; charset=UTF-8",
+		},
+		{
+			name:     "not modified",
+			method:   fasthttp.MethodGet,
+			header:   map[string]string{"If-None-Match": "*"},
+			wantCode: fasthttp.StatusNotModified,
+		},
+		{
+			name:            "compression",
+			method:          fasthttp.MethodGet,
+			header:          map[string]string{"Accept-Encoding": "gzip"},
+			wantCode:        fasthttp.StatusOK,
+			wantContentType: "application/json; charset=UTF-8",
+			wantEncoding:    "gzip",
+			wantBody:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := output.New(
+				output.WithEncoders(output.JSON, output.XML),
+				output.WithETags(true),
+				output.WithCompression(1),
+			)
+
+			var ctx fasthttp.RequestCtx
+			ctx.Request.Header.SetMethod(tt.method)
+			ctx.Request.SetRequestURI("/users?page=1")
+			for k, v := range tt.header {
+				ctx.Request.Header.Set(k, v)
+			}
+
+			if err := r.DataFound(map[string]string{"name": "a"}, NewResponseWriter(&ctx)); err != nil {
+				t.Fatal(err)
+			}
+
+			res := &ctx.Response
+			if res.StatusCode() != tt.wantCode {
+				t.Fatalf("got status %d, want %d", res.StatusCode(), tt.wantCode)
+			}
+			if got := string(res.Header.ContentType()); tt.wantContentType != "" && got != tt.wantContentType {
+				t.Fatalf("got Content-Type %q, want %q", got, tt.wantContentType)
+			}
+			if got := string(res.Header.Peek("Content-Encoding")); got != tt.wantEncoding {
+				t.Fatalf("got Content-Encoding %q, want %q", got, tt.wantEncoding)
+			}
+			if got := len(res.Body()) > 0; got != tt.wantBody {
+				t.Fatalf("got body %q, want body %v", res.Body(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestNewResponseWriterInvalidURL(t *testing.T) {
+	var ctx fasthttp.RequestCtx
+	ctx.Request.Header.SetMethod(fasthttp.MethodHead)
+	ctx.Request.Header.SetRequestURI("/%zz")
+
+	//The method is still used even though the URL cannot be parsed.
+	if err := output.DataFound("a", NewResponseWriter(&ctx)); err != nil {
+		t.Fatal(err)
+	}
+	if len(ctx.Response.Body()) != 0 {
+		t.Fatalf("got body %q, want none", ctx.Response.Body())
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Package outputgrpc converts between output ErrorPayloads and gRPC statuses, so that
+services exposing both REST and gRPC APIs return semantically identical errors.
+
+Convert an ErrorPayload, and the HTTP status code it would be sent with, to a gRPC
+error:
+
+	return nil, outputgrpc.Error(ep, http.StatusNotFound)
+
+Convert a gRPC error, for example from a backend service, to an ErrorPayload:
+
+	ep, code := outputgrpc.FromStatus(status.Convert(err))
+	output.Send(output.Payload{Type: "error", ErrorData: ep}, w, code)
+
+The ErrorPayload's Code is sent as the Reason of an ErrorInfo detail, so that it
+round trips. Fields are sent as a BadRequest detail, RetryAfterSeconds as a RetryInfo
+detail, and DocsURL as a Help detail. A RetryInfo detail is also sent for Retryable
+errors. The Rule of each FieldError is not sent since BadRequest has no equivalent.
+*/
+package outputgrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/c9845/output"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// metadataError is the ErrorInfo metadata key the ErrorPayload's Error is stored in.
+// Other metadata keys are the ErrorPayload's Details.
+const metadataError = "error"
+
+// errorCodes maps the codes of the errors defined in the output package to gRPC
+// codes.
+var errorCodes = map[string]codes.Code{
+	"inputInvalid":       codes.InvalidArgument,
+	"alreadyExists":      codes.AlreadyExists,
+	"badRequest":         codes.InvalidArgument,
+	"unauthorized":       codes.Unauthenticated,
+	"forbidden":          codes.PermissionDenied,
+	"notFound":           codes.NotFound,
+	"conflict":           codes.Aborted,
+	"unprocessable":      codes.FailedPrecondition,
+	"methodNotAllowed":   codes.Unimplemented,
+	"serviceUnavailable": codes.Unavailable,
+	"maintenance":        codes.Unavailable,
+	"timeout":            codes.DeadlineExceeded,
+	"validation":         codes.InvalidArgument,
+	"panic":              codes.Internal,
+	"responseTooLarge":   codes.ResourceExhausted,
+	"schemaMismatch":     codes.Internal,
+}
+
+// grpcErrorCodes maps gRPC codes to the codes of the errors defined in the output
+// package. This is used when a status has no ErrorInfo detail.
+var grpcErrorCodes = map[codes.Code]string{
+	codes.InvalidArgument:    "badRequest",
+	codes.AlreadyExists:      "alreadyExists",
+	codes.Unauthenticated:    "unauthorized",
+	codes.PermissionDenied:   "forbidden",
+	codes.NotFound:           "notFound",
+	codes.Aborted:            "conflict",
+	codes.FailedPrecondition: "unprocessable",
+	codes.Unimplemented:      "methodNotAllowed",
+	codes.Unavailable:        "serviceUnavailable",
+	codes.DeadlineExceeded:   "timeout",
+}
+
+// httpCodes maps HTTP status codes to gRPC codes.
+var httpCodes = map[int]codes.Code{
+	http.StatusOK:                  codes.OK,
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusMethodNotAllowed:    codes.Unimplemented,
+	http.StatusConflict:            codes.Aborted,
+	http.StatusPreconditionFailed:  codes.FailedPrecondition,
+	http.StatusUnprocessableEntity: codes.InvalidArgument,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	499:                            codes.Canceled,
+	http.StatusInternalServerError: codes.Internal,
+	http.StatusNotImplemented:      codes.Unimplemented,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+}
+
+// grpcHTTPCodes maps gRPC codes to HTTP status codes. This matches the mapping used
+// by grpc-gateway.
+var grpcHTTPCodes = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+}
+
+// HTTPToCode returns the gRPC code for an HTTP status code. Unknown 4xx codes are
+// mapped to InvalidArgument, unknown 5xx codes to Internal, and anything else to
+// Unknown.
+func HTTPToCode(httpCode int) codes.Code {
+	if c, ok := httpCodes[httpCode]; ok {
+		return c
+	}
+
+	switch {
+	case httpCode >= 400 && httpCode < 500:
+		return codes.InvalidArgument
+	case httpCode >= 500:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
+
+// CodeToHTTP returns the HTTP status code for a gRPC code.
+func CodeToHTTP(c codes.Code) int {
+	if h, ok := grpcHTTPCodes[c]; ok {
+		return h
+	}
+
+	return http.StatusInternalServerError
+}
+
+// ToStatus converts ep to a gRPC status. The gRPC code is based on ep's Code, for
+// errors defined in the output package, otherwise on httpCode, the HTTP status code
+// the error would be sent with. The status's message is ep's Message, or Error if
+// no Message was provided.
+func ToStatus(ep output.ErrorPayload, httpCode int) *status.Status {
+	c, ok := errorCodes[ep.Code]
+	if !ok {
+		c = HTTPToCode(httpCode)
+	}
+
+	msg := ep.Message
+	if msg == "" {
+		msg = ep.Error
+	}
+
+	s := status.New(c, msg)
+
+	var details []protoadapt.MessageV1
+
+	info := &errdetails.ErrorInfo{
+		Reason:   ep.Code,
+		Metadata: map[string]string{},
+	}
+	if ep.Error != "" {
+		info.Metadata[metadataError] = ep.Error
+	}
+	for k, v := range ep.Details {
+		info.Metadata[k] = detailString(v)
+	}
+	if info.Reason != "" || len(info.Metadata) > 0 {
+		details = append(details, info)
+	}
+
+	if len(ep.Fields) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, f := range ep.Fields {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       f.Field,
+				Description: f.Message,
+			})
+		}
+		details = append(details, br)
+	}
+
+	if ep.Retryable || ep.RetryAfterSeconds > 0 {
+		details = append(details, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(time.Duration(ep.RetryAfterSeconds) * time.Second),
+		})
+	}
+
+	if ep.DocsURL != "" {
+		details = append(details, &errdetails.Help{
+			Links: []*errdetails.Help_Link{{Description: "Documentation", Url: ep.DocsURL}},
+		})
+	}
+
+	if len(details) == 0 {
+		return s
+	}
+
+	//Only fails if a detail cannot be marshalled, which cannot happen with the
+	//detail types used above.
+	if sd, err := s.WithDetails(details...); err == nil {
+		return sd
+	}
+
+	return s
+}
+
+// Error converts ep to a gRPC error. See ToStatus.
+func Error(ep output.ErrorPayload, httpCode int) error {
+	return ToStatus(ep, httpCode).Err()
+}
+
+// FromStatus converts a gRPC status to an ErrorPayload and the HTTP status code to
+// send it with. This is the reverse of ToStatus. If s has no ErrorInfo detail, the
+// ErrorPayload's Code is based on the gRPC code.
+func FromStatus(s *status.Status) (ep output.ErrorPayload, httpCode int) {
+	httpCode = CodeToHTTP(s.Code())
+
+	ep.Message = s.Message()
+	ep.Code = grpcErrorCodes[s.Code()]
+	ep.Retryable = s.Code() == codes.Unavailable
+
+	for _, d := range s.Details() {
+		switch t := d.(type) {
+		case *errdetails.ErrorInfo:
+			if t.Reason != "" {
+				ep.Code = t.Reason
+			}
+
+			for k, v := range t.Metadata {
+				if k == metadataError {
+					ep.Error = v
+					continue
+				}
+
+				if ep.Details == nil {
+					ep.Details = map[string]interface{}{}
+				}
+				ep.Details[k] = v
+			}
+
+		case *errdetails.BadRequest:
+			for _, v := range t.FieldViolations {
+				ep.Fields = append(ep.Fields, output.FieldError{
+					Field:   v.Field,
+					Message: v.Description,
+				})
+			}
+
+		case *errdetails.RetryInfo:
+			ep.Retryable = true
+			ep.RetryAfterSeconds = int((t.RetryDelay.AsDuration() + time.Second - 1) / time.Second)
+
+		case *errdetails.Help:
+			if len(t.Links) > 0 {
+				ep.DocsURL = t.Links[0].Url
+			}
+		}
+	}
+
+	return
+}
+
+// detailString returns v, a value from an ErrorPayload's Details, as a string for
+// ErrorInfo metadata. Strings are used as is, other values are JSON encoded.
+func detailString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
@@ -0,0 +1,58 @@
+/*
+Package outputkafka publishes output Payloads to Kafka, so that asynchronous
+consumers receive the same Payload that HTTP clients do.
+
+Use a Publisher with a Responder:
+
+	w := &kafka.Writer{Addr: kafka.TCP("localhost:9092")}
+	pub := outputkafka.New(w, "orders.")
+
+	err := r.Publish(ctx, pub, output.Payload{OK: true, Type: "orderShipped", Data: order})
+
+Each Payload is sent to the topic named by the prefix followed by the Payload's
+Type, "orders.orderShipped" above. If the kafka.Writer has a Topic set, Payloads are
+instead sent to that topic with the Payload's Type as the message's key.
+*/
+package outputkafka
+
+import (
+	"context"
+
+	"github.com/c9845/output"
+	"github.com/segmentio/kafka-go"
+)
+
+// Publisher is an output.Publisher that sends Payloads to Kafka.
+type Publisher struct {
+	w           *kafka.Writer
+	topicPrefix string
+}
+
+// New returns a Publisher that sends Payloads using w. topicPrefix is prepended to
+// each Payload's Type to get the topic, unless w has a Topic set.
+func New(w *kafka.Writer, topicPrefix string) *Publisher {
+	return &Publisher{
+		w:           w,
+		topicPrefix: topicPrefix,
+	}
+}
+
+// Publish implements output.Publisher.
+func (p *Publisher) Publish(ctx context.Context, m output.Message) error {
+	km := kafka.Message{
+		Value: m.Body,
+		Headers: []kafka.Header{
+			{Key: "Content-Type", Value: []byte(m.ContentType)},
+			{Key: "Type", Value: []byte(m.Type)},
+		},
+	}
+
+	//A topic cannot be set on both the writer and the message.
+	if p.w.Topic != "" {
+		km.Key = []byte(m.Type)
+	} else {
+		km.Topic = p.topicPrefix + m.Type
+	}
+
+	return p.w.WriteMessages(ctx, km)
+}
@@ -0,0 +1,66 @@
+/*
+Package outputotel connects output responses to OpenTelemetry traces. The IDs of the
+current trace and span are embedded in each Payload and response headers, and each
+error response is recorded as an event on the current span.
+
+Use Tracer with a Responder:
+
+	r := output.New(output.WithTracer(outputotel.Tracer))
+
+The span is read from the request's context, so the ResponseWriter must carry the
+request (see output.Negotiate), or output.SendCtx or output.ErrorCtx must be used.
+*/
+package outputotel
+
+import (
+	"context"
+
+	"github.com/c9845/output"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EventError is the name of the span event recorded for each error response.
+const EventError = "output.error"
+
+// Tracer is an output.Tracer that uses the OpenTelemetry span stored in the request's
+// context.
+var Tracer output.Tracer = tracer{}
+
+// tracer implements output.Tracer using OpenTelemetry.
+type tracer struct{}
+
+// TraceContext implements output.Tracer.
+func (tracer) TraceContext(ctx context.Context) (traceID, spanID string) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	traceID, spanID = sc.TraceID().String(), sc.SpanID().String()
+	return
+}
+
+// RecordError implements output.Tracer.
+func (tracer) RecordError(ctx context.Context, p *output.Payload, responseCode int) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.Int("http.response.status_code", responseCode),
+		attribute.String("output.type", p.Type),
+	}
+	if p.ErrorData.Code != "" {
+		attrs = append(attrs, attribute.String("output.error.code", p.ErrorData.Code))
+	}
+	if p.ErrorData.Error != "" {
+		attrs = append(attrs, attribute.String("output.error", p.ErrorData.Error))
+	}
+	if p.ErrorData.Message != "" {
+		attrs = append(attrs, attribute.String("output.error.message", p.ErrorData.Message))
+	}
+
+	span.AddEvent(EventError, trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,357 @@
+/*
+Package outputproto encodes output Payloads as protobuf. The schema of the encoded
+Payload is defined in payload.proto so that clients, such as gRPC-gateway style
+clients, can share one response contract with REST handlers.
+
+Use Encoder with a Responder:
+
+	r := output.New(output.WithEncoders(output.JSON, outputproto.Encoder))
+
+The Payload's Data is stored as a google.protobuf.Any. If Data is a proto.Message
+it is stored as is, otherwise it is converted to a google.protobuf.Value based on
+its JSON encoding.
+*/
+package outputproto
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/c9845/output"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Field numbers from payload.proto.
+const (
+	fieldOK        = 1
+	fieldType      = 2
+	fieldData      = 3
+	fieldErrorData = 4
+	fieldDatetime  = 5
+	fieldMeta      = 6
+	fieldErrors    = 7
+	fieldRequestID = 8
+	fieldTraceID   = 9
+	fieldSpanID    = 10
+	fieldDuration  = 11
+	fieldSequence  = 12
+	fieldVersion   = 13
+	fieldWarnings  = 14
+	fieldLinks     = 15
+
+	fieldError   = 1
+	fieldMessage = 2
+	fieldFields  = 3
+	fieldCode    = 4
+	fieldDetails = 5
+	fieldDocsURL = 6
+
+	fieldRetryable         = 7
+	fieldRetryAfterSeconds = 8
+	fieldChain             = 9
+	fieldStack             = 10
+
+	fieldFieldName    = 1
+	fieldFieldRule    = 2
+	fieldFieldMessage = 3
+
+	fieldWarningCode    = 1
+	fieldWarningMessage = 2
+
+	fieldLinksSelf    = 1
+	fieldLinksRelated = 2
+	fieldLinksActions = 3
+
+	fieldMapKey   = 1
+	fieldMapValue = 2
+
+	fieldActionName   = 1
+	fieldActionMethod = 2
+	fieldActionHref   = 3
+)
+
+// Encoder is an output.Encoder that encodes responses as protobuf per payload.proto.
+var Encoder output.Encoder = encoder{}
+
+// encoder encodes responses as protobuf.
+type encoder struct{}
+
+// ContentType implements output.Encoder.
+func (encoder) ContentType() string {
+	return "application/x-protobuf"
+}
+
+// Encode implements output.Encoder.
+func (encoder) Encode(w io.Writer, v interface{}) (err error) {
+	var p *output.Payload
+	switch t := v.(type) {
+	case *output.Payload:
+		p = t
+	case output.Payload:
+		p = &t
+	case proto.Message:
+		//Raw responses, see output.WithRaw, send Data as is.
+		b, innerErr := proto.MarshalOptions{Deterministic: true}.Marshal(t)
+		if innerErr != nil {
+			err = innerErr
+			return
+		}
+
+		_, err = w.Write(b)
+		return
+	default:
+		return fmt.Errorf("outputproto: cannot encode %T", v)
+	}
+
+	b, err := Marshal(p)
+	if err != nil {
+		return
+	}
+
+	_, err = w.Write(b)
+	return
+}
+
+// Marshal returns the protobuf encoding of p per payload.proto.
+func Marshal(p *output.Payload) (b []byte, err error) {
+	if p.OK {
+		b = protowire.AppendTag(b, fieldOK, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeBool(p.OK))
+	}
+
+	b = appendString(b, fieldType, p.Type)
+
+	if p.Data != nil {
+		a, innerErr := dataToAny(p.Data)
+		if innerErr != nil {
+			err = innerErr
+			return
+		}
+
+		d, innerErr := proto.MarshalOptions{Deterministic: true}.Marshal(a)
+		if innerErr != nil {
+			err = innerErr
+			return
+		}
+
+		b = protowire.AppendTag(b, fieldData, protowire.BytesType)
+		b = protowire.AppendBytes(b, d)
+	}
+
+	if !p.ErrorData.IsZero() {
+		e, innerErr := marshalErrorPayload(p.ErrorData)
+		if innerErr != nil {
+			err = innerErr
+			return
+		}
+
+		b = protowire.AppendTag(b, fieldErrorData, protowire.BytesType)
+		b = protowire.AppendBytes(b, e)
+	}
+
+	b = appendString(b, fieldDatetime, p.Datetime)
+
+	if len(p.Meta) > 0 {
+		d, innerErr := marshalStruct(p.Meta)
+		if innerErr != nil {
+			err = innerErr
+			return
+		}
+
+		b = protowire.AppendTag(b, fieldMeta, protowire.BytesType)
+		b = protowire.AppendBytes(b, d)
+	}
+
+	for _, ep := range p.Errors {
+		e, innerErr := marshalErrorPayload(ep)
+		if innerErr != nil {
+			err = innerErr
+			return
+		}
+
+		b = protowire.AppendTag(b, fieldErrors, protowire.BytesType)
+		b = protowire.AppendBytes(b, e)
+	}
+
+	b = appendString(b, fieldRequestID, p.RequestID)
+	b = appendString(b, fieldTraceID, p.TraceID)
+	b = appendString(b, fieldSpanID, p.SpanID)
+
+	if p.DurationMS != 0 {
+		b = protowire.AppendTag(b, fieldDuration, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(p.DurationMS))
+	}
+
+	if p.Sequence != 0 {
+		b = protowire.AppendTag(b, fieldSequence, protowire.VarintType)
+		b = protowire.AppendVarint(b, p.Sequence)
+	}
+
+	b = appendString(b, fieldVersion, p.APIVersion)
+
+	for _, wa := range p.Warnings {
+		var m []byte
+		m = appendString(m, fieldWarningCode, wa.Code)
+		m = appendString(m, fieldWarningMessage, wa.Message)
+
+		b = protowire.AppendTag(b, fieldWarnings, protowire.BytesType)
+		b = protowire.AppendBytes(b, m)
+	}
+
+	if p.Links != nil {
+		b = protowire.AppendTag(b, fieldLinks, protowire.BytesType)
+		b = protowire.AppendBytes(b, marshalLinks(p.Links))
+	}
+
+	return
+}
+
+// marshalErrorPayload returns the protobuf encoding of ep per payload.proto.
+func marshalErrorPayload(ep output.ErrorPayload) (e []byte, err error) {
+	e = appendString(e, fieldError, ep.Error)
+	e = appendString(e, fieldMessage, ep.Message)
+
+	for _, fe := range ep.Fields {
+		var f []byte
+		f = appendString(f, fieldFieldName, fe.Field)
+		f = appendString(f, fieldFieldRule, fe.Rule)
+		f = appendString(f, fieldFieldMessage, fe.Message)
+
+		e = protowire.AppendTag(e, fieldFields, protowire.BytesType)
+		e = protowire.AppendBytes(e, f)
+	}
+
+	e = appendString(e, fieldCode, ep.Code)
+	e = appendString(e, fieldDocsURL, ep.DocsURL)
+
+	if ep.Retryable {
+		e = protowire.AppendTag(e, fieldRetryable, protowire.VarintType)
+		e = protowire.AppendVarint(e, protowire.EncodeBool(ep.Retryable))
+	}
+	if ep.RetryAfterSeconds != 0 {
+		e = protowire.AppendTag(e, fieldRetryAfterSeconds, protowire.VarintType)
+		e = protowire.AppendVarint(e, uint64(ep.RetryAfterSeconds))
+	}
+
+	for _, c := range ep.Chain {
+		e = protowire.AppendTag(e, fieldChain, protowire.BytesType)
+		e = protowire.AppendString(e, c)
+	}
+
+	for _, s := range ep.Stack {
+		e = protowire.AppendTag(e, fieldStack, protowire.BytesType)
+		e = protowire.AppendString(e, s)
+	}
+
+	if len(ep.Details) > 0 {
+		d, innerErr := marshalStruct(ep.Details)
+		if innerErr != nil {
+			err = innerErr
+			return
+		}
+
+		e = protowire.AppendTag(e, fieldDetails, protowire.BytesType)
+		e = protowire.AppendBytes(e, d)
+	}
+
+	return
+}
+
+// marshalLinks returns the protobuf encoding of l per payload.proto.
+func marshalLinks(l *output.Links) (b []byte) {
+	b = appendString(b, fieldLinksSelf, l.Self)
+
+	//Map entries are sorted so the encoding is deterministic.
+	rels := make([]string, 0, len(l.Related))
+	for rel := range l.Related {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	for _, rel := range rels {
+		var e []byte
+		e = appendString(e, fieldMapKey, rel)
+		e = appendString(e, fieldMapValue, l.Related[rel])
+
+		b = protowire.AppendTag(b, fieldLinksRelated, protowire.BytesType)
+		b = protowire.AppendBytes(b, e)
+	}
+
+	for _, a := range l.Actions {
+		var e []byte
+		e = appendString(e, fieldActionName, a.Name)
+		e = appendString(e, fieldActionMethod, a.Method)
+		e = appendString(e, fieldActionHref, a.Href)
+
+		b = protowire.AppendTag(b, fieldLinksActions, protowire.BytesType)
+		b = protowire.AppendBytes(b, e)
+	}
+
+	return
+}
+
+// appendString appends a string field, omitting it if empty as proto3 does.
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+// toGeneric converts v to generic maps, slices, and values using its JSON encoding
+// so it can be stored in a structpb type.
+func toGeneric(v interface{}) (g interface{}, err error) {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(j, &g)
+	return
+}
+
+// marshalStruct returns the protobuf encoding of m as a structpb.Struct, converting
+// m using its JSON encoding.
+func marshalStruct(m map[string]interface{}) (b []byte, err error) {
+	g, err := toGeneric(m)
+	if err != nil {
+		return
+	}
+
+	s, err := structpb.NewStruct(g.(map[string]interface{}))
+	if err != nil {
+		return
+	}
+
+	b, err = proto.MarshalOptions{Deterministic: true}.Marshal(s)
+	return
+}
+
+// dataToAny wraps data in an Any. Data that is not a proto.Message is converted to
+// a structpb.Value using its JSON encoding.
+func dataToAny(data interface{}) (a *anypb.Any, err error) {
+	m, ok := data.(proto.Message)
+	if !ok {
+		generic, innerErr := toGeneric(data)
+		if innerErr != nil {
+			err = innerErr
+			return
+		}
+
+		m, err = structpb.NewValue(generic)
+		if err != nil {
+			return
+		}
+	}
+
+	a, err = anypb.New(m)
+	return
+}
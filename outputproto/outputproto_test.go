@@ -0,0 +1,475 @@
+package outputproto
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/c9845/output"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// payloadDescriptor is payload.proto built as a descriptor so that encoded Payloads
+// can be decoded with protobuf's own decoder.
+func payloadDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	field := func(name string, num int32, typ descriptorpb.FieldDescriptorProto_Type, repeated bool, typeName string) *descriptorpb.FieldDescriptorProto {
+		label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		if repeated {
+			label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+		}
+
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(num),
+			Type:     typ.Enum(),
+			Label:    label.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+
+	const (
+		str   = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		msg   = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+		boolT = descriptorpb.FieldDescriptorProto_TYPE_BOOL
+	)
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("payload.proto"),
+		Package:    proto.String("output"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/any.proto", "google/protobuf/struct.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("ErrorPayload"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("error", 1, str, false, ""),
+					field("message", 2, str, false, ""),
+					field("fields", 3, msg, true, ".output.FieldError"),
+					field("code", 4, str, false, ""),
+					field("details", 5, msg, false, ".google.protobuf.Struct"),
+					field("docs_url", 6, str, false, ""),
+					field("retryable", 7, boolT, false, ""),
+					field("retry_after_seconds", 8, descriptorpb.FieldDescriptorProto_TYPE_INT64, false, ""),
+					field("chain", 9, str, true, ""),
+					field("stack", 10, str, true, ""),
+				},
+			},
+			{
+				Name: proto.String("FieldError"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("field", 1, str, false, ""),
+					field("rule", 2, str, false, ""),
+					field("message", 3, str, false, ""),
+				},
+			},
+			{
+				Name: proto.String("Warning"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("code", 1, str, false, ""),
+					field("message", 2, str, false, ""),
+				},
+			},
+			{
+				Name: proto.String("Links"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("self", 1, str, false, ""),
+					field("related", 2, msg, true, ".output.Links.RelatedEntry"),
+					field("actions", 3, msg, true, ".output.LinkAction"),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("RelatedEntry"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							field("key", 1, str, false, ""),
+							field("value", 2, str, false, ""),
+						},
+						Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+					},
+				},
+			},
+			{
+				Name: proto.String("LinkAction"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, str, false, ""),
+					field("method", 2, str, false, ""),
+					field("href", 3, str, false, ""),
+				},
+			},
+			{
+				Name: proto.String("Payload"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("ok", 1, boolT, false, ""),
+					field("type", 2, str, false, ""),
+					field("data", 3, msg, false, ".google.protobuf.Any"),
+					field("error_data", 4, msg, false, ".output.ErrorPayload"),
+					field("datetime", 5, str, false, ""),
+					field("meta", 6, msg, false, ".google.protobuf.Struct"),
+					field("errors", 7, msg, true, ".output.ErrorPayload"),
+					field("request_id", 8, str, false, ""),
+					field("trace_id", 9, str, false, ""),
+					field("span_id", 10, str, false, ""),
+					field("duration_ms", 11, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, false, ""),
+					field("sequence", 12, descriptorpb.FieldDescriptorProto_TYPE_UINT64, false, ""),
+					field("api_version", 13, str, false, ""),
+					field("warnings", 14, msg, true, ".output.Warning"),
+					field("links", 15, msg, false, ".output.Links"),
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fd.Messages().ByName("Payload")
+}
+
+// decodePayload decodes b with protobuf's decoder and converts the result back to
+// an output.Payload.
+func decodePayload(t *testing.T, md protoreflect.MessageDescriptor, b []byte) (p output.Payload) {
+	t.Helper()
+
+	m := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(b, m); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(m protoreflect.Message, name string) protoreflect.Value {
+		return m.Get(m.Descriptor().Fields().ByName(protoreflect.Name(name)))
+	}
+	has := func(m protoreflect.Message, name string) bool {
+		return m.Has(m.Descriptor().Fields().ByName(protoreflect.Name(name)))
+	}
+	strs := func(l protoreflect.List) (s []string) {
+		for i := 0; i < l.Len(); i++ {
+			s = append(s, l.Get(i).String())
+		}
+		return
+	}
+
+	//Well-known types are decoded by re-encoding the dynamic message.
+	concrete := func(m protoreflect.Message, dst proto.Message) {
+		b, err := proto.Marshal(m.Interface())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := proto.Unmarshal(b, dst); err != nil {
+			t.Fatal(err)
+		}
+	}
+	structMap := func(m protoreflect.Message, name string) map[string]interface{} {
+		if !has(m, name) {
+			return nil
+		}
+
+		var s structpb.Struct
+		concrete(get(m, name).Message(), &s)
+		return s.AsMap()
+	}
+	errorPayload := func(m protoreflect.Message) (ep output.ErrorPayload) {
+		ep = output.ErrorPayload{
+			Code:              get(m, "code").String(),
+			Error:             get(m, "error").String(),
+			Message:           get(m, "message").String(),
+			Details:           structMap(m, "details"),
+			DocsURL:           get(m, "docs_url").String(),
+			Retryable:         get(m, "retryable").Bool(),
+			RetryAfterSeconds: int(get(m, "retry_after_seconds").Int()),
+			Chain:             strs(get(m, "chain").List()),
+			Stack:             strs(get(m, "stack").List()),
+		}
+
+		fields := get(m, "fields").List()
+		for i := 0; i < fields.Len(); i++ {
+			f := fields.Get(i).Message()
+			ep.Fields = append(ep.Fields, output.FieldError{
+				Field:   get(f, "field").String(),
+				Rule:    get(f, "rule").String(),
+				Message: get(f, "message").String(),
+			})
+		}
+		return
+	}
+
+	p = output.Payload{
+		OK:         get(m, "ok").Bool(),
+		Type:       get(m, "type").String(),
+		Datetime:   get(m, "datetime").String(),
+		Meta:       structMap(m, "meta"),
+		RequestID:  get(m, "request_id").String(),
+		TraceID:    get(m, "trace_id").String(),
+		SpanID:     get(m, "span_id").String(),
+		DurationMS: get(m, "duration_ms").Float(),
+		Sequence:   get(m, "sequence").Uint(),
+		APIVersion: get(m, "api_version").String(),
+	}
+
+	if has(m, "data") {
+		var a anypb.Any
+		concrete(get(m, "data").Message(), &a)
+
+		var v structpb.Value
+		if err := a.UnmarshalTo(&v); err != nil {
+			t.Fatal(err)
+		}
+		p.Data = v.AsInterface()
+	}
+
+	if has(m, "error_data") {
+		p.ErrorData = errorPayload(get(m, "error_data").Message())
+	}
+
+	errs := get(m, "errors").List()
+	for i := 0; i < errs.Len(); i++ {
+		p.Errors = append(p.Errors, errorPayload(errs.Get(i).Message()))
+	}
+
+	warnings := get(m, "warnings").List()
+	for i := 0; i < warnings.Len(); i++ {
+		w := warnings.Get(i).Message()
+		p.Warnings = append(p.Warnings, output.Warning{
+			Code:    get(w, "code").String(),
+			Message: get(w, "message").String(),
+		})
+	}
+
+	if has(m, "links") {
+		l := get(m, "links").Message()
+		p.Links = &output.Links{Self: get(l, "self").String()}
+
+		related := get(l, "related").Map()
+		related.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			if p.Links.Related == nil {
+				p.Links.Related = map[string]string{}
+			}
+			p.Links.Related[k.String()] = v.String()
+			return true
+		})
+
+		actions := get(l, "actions").List()
+		for i := 0; i < actions.Len(); i++ {
+			a := actions.Get(i).Message()
+			p.Links.Actions = append(p.Links.Actions, output.LinkAction{
+				Name:   get(a, "name").String(),
+				Method: get(a, "method").String(),
+				Href:   get(a, "href").String(),
+			})
+		}
+	}
+
+	return
+}
+
+// normalize converts the user-defined values of p using their JSON encoding, as
+// Marshal does, so that p can be compared to a decoded Payload.
+func normalize(t *testing.T, p output.Payload) output.Payload {
+	t.Helper()
+
+	generic := func(v interface{}) interface{} {
+		j, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var g interface{}
+		if err := json.Unmarshal(j, &g); err != nil {
+			t.Fatal(err)
+		}
+		return g
+	}
+
+	if p.Data != nil {
+		p.Data = generic(p.Data)
+	}
+	if p.Meta != nil {
+		p.Meta = generic(p.Meta).(map[string]interface{})
+	}
+	if p.ErrorData.Details != nil {
+		p.ErrorData.Details = generic(p.ErrorData.Details).(map[string]interface{})
+	}
+
+	return p
+}
+
+func TestPayloadDescriptor(t *testing.T) {
+	//The descriptor used to decode Payloads must match payload.proto.
+	schema, err := os.ReadFile("payload.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := payloadDescriptor(t).ParentFile()
+	for i := 0; i < file.Messages().Len(); i++ {
+		m := file.Messages().Get(i)
+		if !regexp.MustCompile(`(?m)^message ` + string(m.Name()) + ` \{`).Match(schema) {
+			t.Errorf("message %s is not in payload.proto", m.Name())
+		}
+
+		for j := 0; j < m.Fields().Len(); j++ {
+			f := m.Fields().Get(j)
+			if f.IsMap() {
+				continue
+			}
+
+			pattern := fmt.Sprintf(`(?m)^  (repeated )?[\w.]+ %s = %d;`, f.Name(), f.Number())
+			if !regexp.MustCompile(pattern).Match(schema) {
+				t.Errorf("field %s.%s = %d is not in payload.proto", m.Name(), f.Name(), f.Number())
+			}
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	md := payloadDescriptor(t)
+
+	tests := []struct {
+		name string
+		p    output.Payload
+	}{
+		{
+			name: "empty",
+			p:    output.Payload{},
+		},
+		{
+			name: "success",
+			p: output.Payload{
+				OK:       true,
+				Type:     "dataFound",
+				Data:     map[string]interface{}{"name": "a", "age": 30, "tags": []string{"b", "c"}, "nil": nil},
+				Datetime: "2024-01-02T03:04:05.000Z",
+			},
+		},
+		{
+			name: "scalar data",
+			p:    output.Payload{OK: true, Type: "insertOK", Data: int64(42)},
+		},
+		{
+			name: "error",
+			p: output.Payload{
+				Type: "error",
+				ErrorData: output.ErrorPayload{
+					Code:              "validation",
+					Error:             "validation error",
+					Message:           "Fix the fields.",
+					Fields:            []output.FieldError{{Field: "name", Rule: "required", Message: "Required."}, {Field: "age"}},
+					Details:           map[string]interface{}{"id": 1, "nested": map[string]interface{}{"a": true}},
+					DocsURL:           "https://example.com/errors/validation",
+					Retryable:         true,
+					RetryAfterSeconds: 30,
+					Chain:             []string{"a", "b"},
+					Stack:             []string{"main.go:1"},
+				},
+			},
+		},
+		{
+			name: "multiple errors",
+			p: output.Payload{
+				Type:      "error",
+				ErrorData: output.ErrorPayload{Code: "multiple"},
+				Errors:    []output.ErrorPayload{{Code: "notFound", Message: "a"}, {Code: "conflict", Message: "b"}},
+			},
+		},
+		{
+			name: "envelope fields",
+			p: output.Payload{
+				OK:         true,
+				Type:       "dataFound",
+				Warnings:   []output.Warning{{Code: "limitCapped", Message: "Capped."}, {Message: "Other."}},
+				Meta:       map[string]interface{}{"pagination": map[string]interface{}{"page": 1}, "region": "us"},
+				RequestID:  "req1",
+				TraceID:    "trace1",
+				SpanID:     "span1",
+				DurationMS: 12.5,
+				APIVersion: "v2",
+				Sequence:   1 << 40,
+				Datetime:   "2024-01-02T03:04:05.000Z",
+			},
+		},
+		{
+			name: "links",
+			p: output.Payload{
+				OK:   true,
+				Type: "dataFound",
+				Links: &output.Links{
+					Self:    "/users/1",
+					Related: map[string]string{"orders": "/users/1/orders", "account": "/accounts/2"},
+					Actions: []output.LinkAction{{Name: "delete", Method: "DELETE", Href: "/users/1"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Marshal(&tt.p)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := decodePayload(t, md, b)
+			want := normalize(t, tt.p)
+
+			//Links have unexported fields so they are compared separately.
+			gotLinks, wantLinks := got.Links, want.Links
+			got.Links, want.Links = nil, nil
+			if (gotLinks == nil) != (wantLinks == nil) {
+				t.Fatalf("got Links %+v, want %+v", gotLinks, wantLinks)
+			}
+			if gotLinks != nil {
+				if gotLinks.Self != wantLinks.Self || !reflect.DeepEqual(gotLinks.Related, wantLinks.Related) || !reflect.DeepEqual(gotLinks.Actions, wantLinks.Actions) {
+					t.Fatalf("got Links %+v, want %+v", gotLinks, wantLinks)
+				}
+			}
+
+			if !reflect.DeepEqual(got.Data, want.Data) {
+				t.Fatalf("got Data %#v, want %#v", got.Data, want.Data)
+			}
+			if !reflect.DeepEqual(got.ErrorData, want.ErrorData) {
+				t.Fatalf("got ErrorData %#v, want %#v", got.ErrorData, want.ErrorData)
+			}
+			if !reflect.DeepEqual(got.Errors, want.Errors) {
+				t.Fatalf("got Errors %#v, want %#v", got.Errors, want.Errors)
+			}
+			if !reflect.DeepEqual(got.Meta, want.Meta) {
+				t.Fatalf("got Meta %#v, want %#v", got.Meta, want.Meta)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestMarshalProtoData(t *testing.T) {
+	md := payloadDescriptor(t)
+
+	p := output.Payload{OK: true, Type: "dataFound", Data: structpb.NewStringValue("a")}
+	b, err := Marshal(&p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Data that is a proto.Message is stored as is.
+	got := decodePayload(t, md, b)
+	if got.Data != "a" {
+		t.Fatalf("got Data %#v, want %q", got.Data, "a")
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Package outputstatsd sends metrics about output responses to StatsD, for teams that
+do not run Prometheus. Metrics are sent using the DogStatsD format, with tags, which
+is supported by Datadog, Telegraf, and most other StatsD servers.
+
+Use a Sink with a Responder:
+
+	s, err := outputstatsd.New("127.0.0.1:8125", outputstatsd.WithPrefix("api."))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer s.Close()
+
+	r := output.New(output.WithMetrics(s))
+
+For each response, a count metric named "responses" and a timing metric named
+"response_time" are sent, both tagged with the response's message type and HTTP
+status code.
+*/
+package outputstatsd
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metric names.
+const (
+	MetricResponses    = "responses"
+	MetricResponseTime = "response_time"
+)
+
+// Sink is an output.MetricsSink that sends metrics to a StatsD server over UDP.
+type Sink struct {
+	conn   net.Conn
+	prefix string
+	tags   []string
+}
+
+// Option is used to configure a Sink when calling New.
+type Option func(*Sink)
+
+// WithPrefix sets the prefix added to each metric's name, for example "api.".
+func WithPrefix(prefix string) Option {
+	return func(s *Sink) {
+		s.prefix = prefix
+	}
+}
+
+// WithTags sets tags, formatted as "key:value", added to every metric. This is
+// typically used for tags such as the service or environment. Characters that would
+// break the DogStatsD format are replaced with underscores.
+func WithTags(tags ...string) Option {
+	return func(s *Sink) {
+		for _, t := range tags {
+			key, value, ok := strings.Cut(t, ":")
+			if ok {
+				t = tagValue(key) + ":" + tagValue(value)
+			} else {
+				t = tagValue(t)
+			}
+
+			s.tags = append(s.tags, t)
+		}
+	}
+}
+
+// New returns a Sink that sends metrics to the StatsD server at addr.
+func New(addr string, opts ...Option) (s *Sink, err error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return
+	}
+
+	s = &Sink{conn: conn}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return
+}
+
+// Close closes the connection to the StatsD server.
+func (s *Sink) Close() error {
+	return s.conn.Close()
+}
+
+// ObserveResponse implements output.MetricsSink.
+func (s *Sink) ObserveResponse(msgType string, responseCode int, duration time.Duration) {
+	tags := append([]string{
+		"type:" + tagValue(msgType),
+		"status:" + strconv.Itoa(responseCode),
+	}, s.tags...)
+
+	ms := strconv.FormatFloat(float64(duration)/float64(time.Millisecond), 'f', 3, 64)
+
+	//Both metrics are sent in one packet. Errors are ignored since metrics are
+	//best effort and UDP does not report delivery failures anyway.
+	var b strings.Builder
+	s.write(&b, MetricResponses, "1", "c", tags)
+	b.WriteByte('\n')
+	s.write(&b, MetricResponseTime, ms, "ms", tags)
+
+	s.conn.Write([]byte(b.String()))
+}
+
+// write writes a metric in the DogStatsD format.
+func (s *Sink) write(b *strings.Builder, name, value, typ string, tags []string) {
+	b.WriteString(s.prefix)
+	b.WriteString(name)
+	b.WriteByte(':')
+	b.WriteString(value)
+	b.WriteByte('|')
+	b.WriteString(typ)
+
+	if len(tags) > 0 {
+		b.WriteString("|#")
+		b.WriteString(strings.Join(tags, ","))
+	}
+}
+
+// tagReplacer replaces the characters that delimit metrics, and tags, in the
+// DogStatsD format.
+var tagReplacer = strings.NewReplacer(":", "_", "|", "_", ",", "_", "\n", "_")
+
+// tagValue returns v with characters that would break the DogStatsD format
+// replaced. Message types are user-defined so they may contain these characters.
+func tagValue(v string) string {
+	return tagReplacer.Replace(v)
+}
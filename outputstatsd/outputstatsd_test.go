@@ -0,0 +1,78 @@
+package outputstatsd
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestObserveResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		msgType  string
+		code     int
+		duration time.Duration
+		want     string
+	}{
+		{
+			name:     "default",
+			msgType:  "dataFound",
+			code:     200,
+			duration: 1500 * time.Microsecond,
+			want:     "responses:1|c|#type:dataFound,status:200\nresponse_time:1.500|ms|#type:dataFound,status:200",
+		},
+		{
+			name:     "prefix and tags",
+			opts:     []Option{WithPrefix("api."), WithTags("env:prod", "service")},
+			msgType:  "error",
+			code:     500,
+			duration: time.Millisecond,
+			want:     "api.responses:1|c|#type:error,status:500,env:prod,service\napi.response_time:1.000|ms|#type:error,status:500,env:prod,service",
+		},
+		{
+			name:     "message type sanitized",
+			msgType:  "a:b|c,d\ne",
+			code:     200,
+			duration: time.Millisecond,
+			want:     "responses:1|c|#type:a_b_c_d_e,status:200\nresponse_time:1.000|ms|#type:a_b_c_d_e,status:200",
+		},
+		{
+			name:     "tags sanitized",
+			opts:     []Option{WithTags("env:prod|x,y", "a|b")},
+			msgType:  "dataFound",
+			code:     200,
+			duration: time.Millisecond,
+			want:     "responses:1|c|#type:dataFound,status:200,env:prod_x_y,a_b\nresponse_time:1.000|ms|#type:dataFound,status:200,env:prod_x_y,a_b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer pc.Close()
+
+			s, err := New(pc.LocalAddr().String(), tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			s.ObserveResponse(tt.msgType, tt.code, tt.duration)
+
+			pc.SetReadDeadline(time.Now().Add(time.Second))
+			buf := make([]byte, 1024)
+			n, _, err := pc.ReadFrom(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := string(buf[:n]); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Package outputtest provides helpers for testing handlers that send responses with
+the output package, so that tests do not need to parse the Payload themselves.
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	outputtest.AssertSuccess(t, rec, output.TypeDataFound)
+	users := outputtest.DecodeData[[]User](t, rec)
+
+The helpers read the recorded body without consuming it, so more than one helper
+can be used with the same recorder.
+*/
+package outputtest
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/c9845/output"
+)
+
+// Decode decodes the Payload recorded by rec. The test fails immediately if the
+// body is not a Payload.
+func Decode(t testing.TB, rec *httptest.ResponseRecorder) *output.Payload {
+	t.Helper()
+
+	_, p := decode[interface{}](t, rec)
+	return p
+}
+
+// DecodeData decodes the Payload recorded by rec and returns its Data as a T. The
+// test fails immediately if the body is not a Payload or Data is not a T.
+func DecodeData[T any](t testing.TB, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	data, _ := decode[T](t, rec)
+	return data
+}
+
+// AssertSuccess checks that rec recorded a successful response with the given
+// message type and a 2xx status code. The Payload is returned for further checks.
+//
+//	outputtest.AssertSuccess(t, rec, "dataFound")
+func AssertSuccess(t testing.TB, rec *httptest.ResponseRecorder, msgType output.MessageType) *output.Payload {
+	t.Helper()
+
+	p := Decode(t, rec)
+	if !p.OK {
+		t.Errorf("outputtest: expected OK response, got error %q: %s", p.ErrorData.Code, errorText(p.ErrorData))
+	}
+	if p.Type != string(msgType) {
+		t.Errorf("outputtest: expected message type %q, got %q", msgType, p.Type)
+	}
+	if rec.Code < 200 || rec.Code > 299 {
+		t.Errorf("outputtest: expected 2xx status code, got %d", rec.Code)
+	}
+
+	return p
+}
+
+// AssertError checks that rec recorded an error response with a status code of 400
+// or greater. The Payload is returned for further checks.
+func AssertError(t testing.TB, rec *httptest.ResponseRecorder) *output.Payload {
+	t.Helper()
+
+	p := Decode(t, rec)
+	if p.OK {
+		t.Errorf("outputtest: expected error response, got OK response of type %q", p.Type)
+	}
+	if rec.Code < 400 {
+		t.Errorf("outputtest: expected error status code, got %d", rec.Code)
+	}
+
+	return p
+}
+
+// AssertErrorType checks that rec recorded an error response with the given error
+// code, see output.RegisterErrorCode. The Payload is returned for further checks.
+//
+//	outputtest.AssertErrorType(t, rec, "inputInvalid")
+func AssertErrorType(t testing.TB, rec *httptest.ResponseRecorder, code string) *output.Payload {
+	t.Helper()
+
+	p := AssertError(t, rec)
+	if p.ErrorData.Code != code {
+		t.Errorf("outputtest: expected error code %q, got %q: %s", code, p.ErrorData.Code, errorText(p.ErrorData))
+	}
+
+	return p
+}
+
+// decode decodes the Payload recorded by rec, with Data decoded into a T.
+func decode[T any](t testing.TB, rec *httptest.ResponseRecorder) (T, *output.Payload) {
+	t.Helper()
+
+	data, p, err := output.Parse[T](bytes.NewReader(rec.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("outputtest: could not decode payload: %v\nbody: %s", err, rec.Body.String())
+	}
+
+	return data, p
+}
+
+// errorText returns the message of e for use in failure messages.
+func errorText(e output.ErrorPayload) string {
+	if e.Message != "" {
+		return e.Message
+	}
+
+	return e.Error
+}
@@ -0,0 +1,59 @@
+/*
+Package outputwebsocket adapts websocket connections from gorilla/websocket and
+nhooyr.io/websocket for use with output.WebSocket, so that realtime endpoints send
+the same Payloads as REST endpoints.
+
+	c, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return
+	}
+	defer c.Close()
+
+	ws := output.NewWebSocket(outputwebsocket.Gorilla(c))
+	ws.Success(req.Context(), "priceChanged", price)
+*/
+package outputwebsocket
+
+import (
+	"context"
+
+	"github.com/c9845/output"
+	gorilla "github.com/gorilla/websocket"
+	"nhooyr.io/websocket"
+)
+
+// gorillaConn adapts a gorilla/websocket connection.
+type gorillaConn struct {
+	c *gorilla.Conn
+}
+
+// Gorilla returns c as an output.WebSocketConn. The context's deadline, if any, is
+// used as the write deadline since gorilla/websocket does not use contexts.
+func Gorilla(c *gorilla.Conn) output.WebSocketConn {
+	return gorillaConn{c: c}
+}
+
+// WriteText implements output.WebSocketConn.
+func (g gorillaConn) WriteText(ctx context.Context, b []byte) error {
+	deadline, _ := ctx.Deadline()
+	if err := g.c.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+
+	return g.c.WriteMessage(gorilla.TextMessage, b)
+}
+
+// nhooyrConn adapts a nhooyr.io/websocket connection.
+type nhooyrConn struct {
+	c *websocket.Conn
+}
+
+// Nhooyr returns c as an output.WebSocketConn.
+func Nhooyr(c *websocket.Conn) output.WebSocketConn {
+	return nhooyrConn{c: c}
+}
+
+// WriteText implements output.WebSocketConn.
+func (n nhooyrConn) WriteText(ctx context.Context, b []byte) error {
+	return n.c.Write(ctx, websocket.MessageText, b)
+}
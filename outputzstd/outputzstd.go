@@ -0,0 +1,64 @@
+/*
+Package outputzstd compresses output responses with zstd, which compresses and
+decompresses faster than gzip and brotli at a similar ratio. This is well suited to
+service-to-service traffic where both ends are under your control.
+
+Use Compressor with a Responder that has compression enabled:
+
+	r := output.New(
+		output.WithCompression(0),
+		output.WithCompressors(outputzstd.Compressor, output.Gzip),
+	)
+*/
+package outputzstd
+
+import (
+	"io"
+
+	"github.com/c9845/output"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compressor is an output.Compressor for the zstd content coding using zstd's
+// default level.
+var Compressor output.Compressor
+
+func init() {
+	c, err := New()
+	if err != nil {
+		//This only happens if the default options are invalid.
+		panic(err)
+	}
+
+	Compressor = c
+}
+
+// compressor implements output.Compressor using zstd.
+type compressor struct {
+	enc *zstd.Encoder
+}
+
+// New returns an output.Compressor for the zstd content coding configured with
+// opts, for example zstd.WithEncoderLevel.
+func New(opts ...zstd.EOption) (c output.Compressor, err error) {
+	//Responses are compressed in one call so a single, concurrent safe, encoder
+	//is used via EncodeAll.
+	enc, err := zstd.NewWriter(nil, opts...)
+	if err != nil {
+		return
+	}
+
+	c = compressor{enc: enc}
+	return
+}
+
+// Encoding implements output.Compressor.
+func (c compressor) Encoding() string {
+	return "zstd"
+}
+
+// Compress implements output.Compressor.
+func (c compressor) Compress(dst io.Writer, src []byte) (err error) {
+	_, err = dst.Write(c.enc.EncodeAll(src, nil))
+	return
+}
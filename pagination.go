@@ -0,0 +1,129 @@
+package output
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// MetaPagination is the key in a Payload's Meta where pagination details are
+// stored by DataFoundPaginated.
+const MetaPagination = "Pagination"
+
+// Pagination describes which part of a larger set of results is being returned.
+// This provides a consistent format for paginated responses instead of each
+// endpoint defining its own format within Data.
+type Pagination struct {
+	//Total is the total number of results across all pages.
+	Total int64
+
+	//Page is the current page number, typically starting at 1.
+	Page int
+
+	//PerPage is the maximum number of results per page.
+	PerPage int
+
+	//TotalPages is the total number of pages. If this is not provided, it is
+	//calculated from Total and PerPage.
+	TotalPages int
+
+	//Next is a cursor, or other token, used to request the next page of results.
+	//This is blank if there is no next page or cursors are not used.
+	Next string `json:",omitempty"`
+
+	//Prev is a cursor, or other token, used to request the previous page of
+	//results. This is blank if there is no previous page or cursors are not used.
+	Prev string `json:",omitempty"`
+}
+
+// DataFoundPaginated is used to send back one page of data. The pagination details
+// are stored in the Payload's Meta with the MetaPagination key.
+func DataFoundPaginated(data interface{}, page Pagination, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.DataFoundPaginated(data, page, w, opts...)
+	return
+}
+
+// DataFoundPaginated sends one page of data. See the package-level
+// DataFoundPaginated.
+func (r *Responder) DataFoundPaginated(data interface{}, page Pagination, w http.ResponseWriter, opts ...SendOption) (err error) {
+	if page.TotalPages == 0 && page.PerPage > 0 {
+		page.TotalPages = int((page.Total + int64(page.PerPage) - 1) / int64(page.PerPage))
+	}
+
+	if r.paginationLinks {
+		if req := requestFrom(w); req != nil {
+			if links := paginationLinks(req, page); links != "" {
+				opts = append([]SendOption{WithHeader("Link", links)}, opts...)
+			}
+		}
+	}
+
+	p := r.newPayload(true, TypeDataFound, data, ErrorPayload{})
+	p.Meta = map[string]interface{}{
+		MetaPagination: page,
+	}
+
+	err = r.send(&p, w, r.successCode, opts...)
+	return
+}
+
+// Query parameters set in the URLs of pagination Link headers.
+const (
+	//LinkPageParam is the query parameter set to a page number when Pagination
+	//has a Page.
+	LinkPageParam = "page"
+
+	//LinkCursorParam is the query parameter set to Pagination's Next or Prev
+	//cursor.
+	LinkCursorParam = "cursor"
+)
+
+// paginationLinks builds the value of a Link header for page based on the URL of
+// req. Page numbers are used for next, prev, first, and last links when the page is
+// known. Cursors, if provided, are used for next and prev links instead of page
+// numbers.
+func paginationLinks(req *http.Request, page Pagination) string {
+	base := *requestURL(req)
+
+	link := func(param, value string) string {
+		u := base
+		q := u.Query()
+		q.Set(param, value)
+
+		//A page number and cursor should not both be used.
+		if param == LinkPageParam {
+			q.Del(LinkCursorParam)
+		} else {
+			q.Del(LinkPageParam)
+		}
+
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	var links []string
+	add := func(rel, u string) {
+		links = append(links, "<"+u+`>; rel="`+rel+`"`)
+	}
+
+	switch {
+	case page.Next != "":
+		add("next", link(LinkCursorParam, page.Next))
+	case page.Page > 0 && page.Page < page.TotalPages:
+		add("next", link(LinkPageParam, strconv.Itoa(page.Page+1)))
+	}
+
+	switch {
+	case page.Prev != "":
+		add("prev", link(LinkCursorParam, page.Prev))
+	case page.Page > 1:
+		add("prev", link(LinkPageParam, strconv.Itoa(page.Page-1)))
+	}
+
+	if page.Page > 0 && page.TotalPages > 0 {
+		add("first", link(LinkPageParam, "1"))
+		add("last", link(LinkPageParam, strconv.Itoa(page.TotalPages)))
+	}
+
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,75 @@
+package output
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPaginationLinks(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		forwardedProto string
+		page           Pagination
+		want           string
+	}{
+		{
+			name:   "middle page",
+			target: "http://example.com/users?page=2&sort=name",
+			page:   Pagination{Page: 2, TotalPages: 3},
+			want: `<http://example.com/users?page=3&sort=name>; rel="next", ` +
+				`<http://example.com/users?page=1&sort=name>; rel="prev", ` +
+				`<http://example.com/users?page=1&sort=name>; rel="first", ` +
+				`<http://example.com/users?page=3&sort=name>; rel="last"`,
+		},
+		{
+			name:   "first page",
+			target: "http://example.com/users",
+			page:   Pagination{Page: 1, TotalPages: 2},
+			want: `<http://example.com/users?page=2>; rel="next", ` +
+				`<http://example.com/users?page=1>; rel="first", ` +
+				`<http://example.com/users?page=2>; rel="last"`,
+		},
+		{
+			name:   "cursors",
+			target: "http://example.com/users?page=4",
+			page:   Pagination{Next: "n", Prev: "p"},
+			want: `<http://example.com/users?cursor=n>; rel="next", ` +
+				`<http://example.com/users?cursor=p>; rel="prev"`,
+		},
+		{
+			name:           "forwarded https",
+			target:         "http://example.com/users",
+			forwardedProto: "https",
+			page:           Pagination{Next: "n"},
+			want:           `<https://example.com/users?cursor=n>; rel="next"`,
+		},
+		{
+			name:   "single page",
+			target: "http://example.com/users",
+			page:   Pagination{},
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(WithPaginationLinks(true))
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.forwardedProto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.forwardedProto)
+			}
+
+			w := httptest.NewRecorder()
+			if err := r.DataFoundPaginated([]int{1}, tt.page, Negotiate(w, req)); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := w.Header().Get("Link"); got != tt.want {
+				t.Fatalf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Parse decodes a Payload read from rd, such as the body of a response from another
+// service, and decodes the Payload's Data into a T in the same step. The returned
+// Payload's Data is set to the returned T.
+//
+//	users, p, err := output.Parse[[]User](resp.Body)
+//	if err != nil {
+//		return err
+//	}
+//	if !p.OK {
+//		return errors.New(p.ErrorData.Message)
+//	}
+//
+// A Payload with OK set to false is not an error, check the Payload's OK and
+// ErrorData. Keys are matched case-insensitively, so Payloads encoded with
+// KeyCaseCamel are decoded, but Payloads encoded with KeyCaseSnake or custom field
+// names are not; use the outputclient subpackage for those.
+func Parse[T any](rd io.Reader) (data T, p *Payload, err error) {
+	//Data is set to a pointer so that it is decoded directly into a T, rather than
+	//into a map and then re-encoded.
+	p = &Payload{Data: &data}
+
+	err = json.NewDecoder(rd).Decode(p)
+	if err != nil {
+		return
+	}
+
+	p.Data = data
+	return
+}
@@ -0,0 +1,73 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// prettyIndent is the indent used when pretty-printing JSON.
+const prettyIndent = "  "
+
+// WithPrettyJSON causes JSON responses to be indented so they are readable by
+// humans, for example in development environments. Data sent with DataFoundReader
+// is read in full, rather than streamed, so that it is indented as well.
+func WithPrettyJSON(b bool) Option {
+	return func(r *Responder) {
+		r.prettyJSON = b
+	}
+}
+
+// WithPrettyParam causes JSON responses to be indented when the request has the
+// query parameter param set to a true value, for example "?pretty=1". This allows
+// humans to get readable responses when using curl without piping the response
+// through jq. The ResponseWriter must carry the request, see Negotiate. As with
+// WithPrettyJSON, Data sent with DataFoundReader is not streamed when indenting.
+func WithPrettyParam(param string) Option {
+	return func(r *Responder) {
+		r.prettyParam = param
+	}
+}
+
+// pretty reports if the response being sent to w should be indented.
+func (r *Responder) pretty(w http.ResponseWriter) bool {
+	if r.prettyJSON {
+		return true
+	}
+
+	if r.prettyParam == "" {
+		return false
+	}
+
+	req := requestFrom(w)
+	if req == nil {
+		return false
+	}
+
+	b, _ := strconv.ParseBool(req.URL.Query().Get(r.prettyParam))
+	return b
+}
+
+// isJSON reports if contentType is JSON, including JSON based types such as
+// application/problem+json.
+func isJSON(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// indentJSON returns j indented. j is returned as is if it cannot be indented.
+func indentJSON(j []byte) []byte {
+	var b bytes.Buffer
+	if err := json.Indent(&b, j, "", prettyIndent); err != nil {
+		return j
+	}
+
+	return b.Bytes()
+}
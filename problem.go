@@ -0,0 +1,140 @@
+package output
+
+import "net/http"
+
+// ProblemDetails is the format of error responses when a Responder is configured
+// with WithProblemDetails. This follows RFC 9457 so that API gateways and clients
+// that understand problem details can handle errors natively.
+//
+// The fields are mapped from the Payload and ErrorPayload that would otherwise have
+// been sent.
+type ProblemDetails struct {
+	//Type is a URI that identifies the problem type. This is always "about:blank"
+	//since the HTTP status code describes the problem.
+	Type string `json:"type"`
+
+	//Title is a short summary of the problem. Since Type is "about:blank", this is
+	//the text of the HTTP status code.
+	Title string `json:"title"`
+
+	//Status is the HTTP status code of the response.
+	Status int `json:"status"`
+
+	//Detail is a human-readable explanation of this occurence of the problem. This
+	//is the ErrorPayload's Message, or Error if no Message was provided.
+	Detail string `json:"detail,omitempty"`
+
+	//Instance is a URI that identifies this occurence of the problem. This is the
+	//path, and query, of the request if the ResponseWriter carries the request (see
+	//Negotiate).
+	Instance string `json:"instance,omitempty"`
+
+	//The fields below are extension members used to retain data from the Payload
+	//that does not map to one of the standard members above.
+
+	//Code is the ErrorPayload's machine-readable code.
+	Code string `json:"code,omitempty"`
+
+	//Error is the ErrorPayload's lower-level error.
+	Error string `json:"error,omitempty"`
+
+	//Fields is the ErrorPayload's Fields, see ErrorValidation.
+	Fields []FieldError `json:"fields,omitempty"`
+
+	//Errors is the Payload's Errors, see ErrorMulti.
+	Errors []ErrorPayload `json:"errors,omitempty"`
+
+	//Links is the Payload's Links, see WithLinks.
+	Links *Links `json:"links,omitempty"`
+
+	//Warnings is the Payload's Warnings, see WithWarning.
+	Warnings []Warning `json:"warnings,omitempty"`
+
+	//Details is the ErrorPayload's Details, see ErrorWithDetails.
+	Details map[string]interface{} `json:"details,omitempty"`
+
+	//DocsURL is the ErrorPayload's DocsURL, see RegisterError.
+	DocsURL string `json:"docsURL,omitempty"`
+
+	//Retryable is the ErrorPayload's Retryable, see WithRetryable.
+	Retryable bool `json:"retryable,omitempty"`
+
+	//RetryAfterSeconds is the ErrorPayload's RetryAfterSeconds, see WithRetryAfter.
+	RetryAfterSeconds int `json:"retryAfterSeconds,omitempty"`
+
+	//Chain is the ErrorPayload's Chain, see WithVerboseErrors.
+	Chain []string `json:"chain,omitempty"`
+
+	//Stack is the ErrorPayload's Stack, see WithStackTraces.
+	Stack []string `json:"stack,omitempty"`
+
+	//MessageType is the Payload's Type.
+	MessageType string `json:"messageType,omitempty"`
+
+	//Data is the Payload's Data, for example the ID sent with ErrorWithID.
+	Data interface{} `json:"data,omitempty"`
+
+	//RequestID is the Payload's RequestID, see RequestIDMiddleware.
+	RequestID string `json:"requestID,omitempty"`
+
+	//TraceID and SpanID are the Payload's TraceID and SpanID, see WithTracer.
+	TraceID string `json:"traceID,omitempty"`
+	SpanID  string `json:"spanID,omitempty"`
+
+	//DurationMS is the Payload's DurationMS, see TimingMiddleware.
+	DurationMS float64 `json:"durationMS,omitempty"`
+
+	//APIVersion is the Payload's APIVersion, see WithAPIVersion.
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	//Sequence is the Payload's Sequence, see WithSequence.
+	Sequence uint64 `json:"sequence,omitempty"`
+
+	//Meta is the Payload's Meta, see WithMeta.
+	Meta map[string]interface{} `json:"meta,omitempty"`
+
+	//Datetime is the Payload's Datetime.
+	Datetime string `json:"datetime,omitempty"`
+}
+
+// newProblemDetails builds a ProblemDetails from a Payload. req is the request being
+// responded to, if known, and is used for the Instance.
+func newProblemDetails(p *Payload, responseCode int, req *http.Request) (pd ProblemDetails) {
+	pd = ProblemDetails{
+		Type:              "about:blank",
+		Title:             http.StatusText(responseCode),
+		Status:            responseCode,
+		Detail:            p.ErrorData.Message,
+		Code:              p.ErrorData.Code,
+		Error:             p.ErrorData.Error,
+		Fields:            p.ErrorData.Fields,
+		Errors:            p.Errors,
+		Warnings:          p.Warnings,
+		Links:             p.Links,
+		Details:           p.ErrorData.Details,
+		DocsURL:           p.ErrorData.DocsURL,
+		Retryable:         p.ErrorData.Retryable,
+		RetryAfterSeconds: p.ErrorData.RetryAfterSeconds,
+		Chain:             p.ErrorData.Chain,
+		Stack:             p.ErrorData.Stack,
+		MessageType:       p.Type,
+		Data:              p.Data,
+		RequestID:         p.RequestID,
+		TraceID:           p.TraceID,
+		SpanID:            p.SpanID,
+		DurationMS:        p.DurationMS,
+		APIVersion:        p.APIVersion,
+		Sequence:          p.Sequence,
+		Meta:              p.Meta,
+		Datetime:          p.Datetime,
+	}
+
+	if pd.Detail == "" {
+		pd.Detail = p.ErrorData.Error
+	}
+	if req != nil && req.URL != nil {
+		pd.Instance = req.URL.RequestURI()
+	}
+
+	return
+}
@@ -0,0 +1,80 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestProblemDetails(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		opts   []SendOption
+		want   ProblemDetails
+	}{
+		{
+			name: "without request",
+			want: ProblemDetails{
+				Type:        "about:blank",
+				Title:       "Internal Server Error",
+				Status:      http.StatusInternalServerError,
+				Detail:      "Check the input.",
+				Error:       "bad input",
+				MessageType: string(TypeError),
+			},
+		},
+		{
+			name:   "instance",
+			target: "/users/1?expand=roles",
+			want: ProblemDetails{
+				Type:        "about:blank",
+				Title:       "Internal Server Error",
+				Status:      http.StatusInternalServerError,
+				Detail:      "Check the input.",
+				Instance:    "/users/1?expand=roles",
+				Error:       "bad input",
+				MessageType: string(TypeError),
+			},
+		},
+		{
+			name: "meta",
+			opts: []SendOption{WithMetaValue("region", "us")},
+			want: ProblemDetails{
+				Type:        "about:blank",
+				Title:       "Internal Server Error",
+				Status:      http.StatusInternalServerError,
+				Detail:      "Check the input.",
+				Error:       "bad input",
+				MessageType: string(TypeError),
+				Meta:        map[string]interface{}{"region": "us"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(WithProblemDetails(true), WithOmitDatetime(true))
+
+			rec := httptest.NewRecorder()
+			var w http.ResponseWriter = rec
+			if tt.target != "" {
+				w = Negotiate(rec, httptest.NewRequest("GET", tt.target, nil))
+			}
+
+			r.Error(errors.New("bad input"), "Check the input.", w, tt.opts...)
+
+			var got ProblemDetails
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			got.Code = ""
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
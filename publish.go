@@ -0,0 +1,100 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrNoMessageType is returned by Publish when the Payload has no Type, since the
+// Type is used to route the message.
+var ErrNoMessageType = errors.New("output: payload has no message type")
+
+// Message is a Payload encoded for a message bus, see Publisher.
+type Message struct {
+	//Type is the Payload's Type. Publishers use this to route the message, for
+	//example as a routing key or as a suffix of a topic.
+	Type string
+
+	//Body is the encoded Payload.
+	Body []byte
+
+	//ContentType is the content type of Body.
+	ContentType string
+}
+
+// Publisher sends Messages to a message bus, such as Kafka or RabbitMQ, so that
+// asynchronous consumers receive the same Payload that HTTP clients do. See the
+// outputkafka and outputamqp packages for Publishers.
+type Publisher interface {
+	//Publish sends m to the message bus.
+	Publish(ctx context.Context, m Message) error
+}
+
+// Publish sends a Payload to a message bus using the default Responder. See
+// Responder.Publish.
+func Publish(ctx context.Context, pub Publisher, p Payload) (err error) {
+	err = std.Publish(ctx, pub, p)
+	return
+}
+
+// Publish sends a Payload to a message bus using pub. The Payload is prepared the
+// same as a response sent with Send: the Datetime, RequestID (from ctx), default
+// Meta, and Sequence are set, sensitive fields are redacted, and keys are encoded
+// per the Responder's configuration. The Payload is always encoded as JSON.
+//
+//	err := r.Publish(ctx, pub, output.Payload{
+//		OK:   true,
+//		Type: "orderShipped",
+//		Data: order,
+//	})
+func (r *Responder) Publish(ctx context.Context, pub Publisher, p Payload) (err error) {
+	if strings.TrimSpace(p.Type) == "" {
+		err = ErrNoMessageType
+		return
+	}
+
+	b, err := r.encodeMessage(ctx, &p)
+	if err != nil {
+		return
+	}
+
+	err = pub.Publish(ctx, Message{
+		Type:        p.Type,
+		Body:        b,
+		ContentType: JSON.ContentType(),
+	})
+	return
+}
+
+// encodeMessage prepares a Payload sent outside of an HTTP response, such as to a
+// message bus or websocket, the same as Send does and encodes it as JSON.
+func (r *Responder) encodeMessage(ctx context.Context, p *Payload) (b []byte, err error) {
+	if strings.TrimSpace(p.Datetime) == "" {
+		p.Datetime = r.timestamp()
+	}
+	if !p.ErrorData.IsZero() || len(p.Errors) > 0 {
+		p.OK = false
+	}
+	if ctx != nil && p.RequestID == "" {
+		p.RequestID = RequestIDFrom(ctx)
+	}
+
+	r.setEnvelopeKeys(p)
+	r.setDefaultMeta(p)
+	p.Sequence = r.nextSequence()
+	p.Data = r.redact(p.Data)
+
+	b, err = p.Bytes()
+	if err != nil {
+		return
+	}
+	if r.canonicalJSON {
+		b = canonicalJSON(b)
+	}
+	if !r.escapeHTML {
+		b = unescapeHTML(b)
+	}
+
+	return
+}
@@ -0,0 +1,44 @@
+package output
+
+import "net/http"
+
+// WithRaw causes only the Payload's Data to be sent, without the rest of the
+// Payload wrapped around it. This is used for endpoints that must match a bare
+// schema mandated by someone else, such as a webhook receiver or health check.
+// Everything else about the response, such as the Encoder, headers, hooks, and
+// metrics, works as usual.
+//
+//	output.DataFound(status, w, output.WithRaw())
+func WithRaw() SendOption {
+	return func(o *sendOptions) {
+		o.raw = true
+	}
+}
+
+// SendRaw sends data without the Payload wrapped around it. See WithRaw.
+func SendRaw(data interface{}, w http.ResponseWriter, responseCode int, opts ...SendOption) (err error) {
+	err = std.SendRaw(data, w, responseCode, opts...)
+	return
+}
+
+// SendRaw sends data without the Payload wrapped around it. See the package-level
+// SendRaw.
+func (r *Responder) SendRaw(data interface{}, w http.ResponseWriter, responseCode int, opts ...SendOption) (err error) {
+	if responseCode < http.StatusContinue {
+		r.log(w, "output.SendRaw: invalid HTTP response code provided", "status", responseCode)
+
+		err = ErrInvalidResponseCode
+		return
+	}
+
+	//The Payload is still built, even though only Data is sent, so that hooks and
+	//metrics receive the same information as other responses.
+	ok, msgType := true, TypeDataFound
+	if responseCode >= http.StatusBadRequest {
+		ok, msgType = false, TypeError
+	}
+
+	opts = append(opts, WithRaw())
+	err = r.buildAndSend(ok, msgType, data, ErrorPayload{}, w, responseCode, opts...)
+	return
+}
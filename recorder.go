@@ -0,0 +1,175 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Recording is a response captured by a Recorder.
+type Recording struct {
+	//Method and Path are the HTTP method and URL path of the request that was
+	//responded to. These are blank unless the ResponseWriter carried the request,
+	//see Negotiate.
+	Method string
+	Path   string
+
+	//ResponseCode is the HTTP status code that was sent.
+	ResponseCode int
+
+	//Payload is the JSON encoded Payload that was sent.
+	Payload json.RawMessage
+
+	//Time is when the response was sent.
+	Time time.Time
+}
+
+// Recorder captures each response sent by a Responder, for reproducing issues
+// reported by clients or building fixtures for tests. Recordings are kept in memory,
+// in a ring buffer of a fixed size, and/or written to an io.Writer, such as a file,
+// as JSON lines. Recordings can be served with ReplayHandler.
+//
+// The Payload is recorded as sent, so sensitive Data is recorded unless it is
+// redacted, see WithRedactedFields.
+type Recorder struct {
+	mu sync.Mutex
+
+	//ring holds the most recent recordings, next is the index the next recording
+	//is stored at, and full reports if ring has wrapped around.
+	ring []Recording
+	next int
+	full bool
+
+	//w is where recordings are written as JSON lines, if set.
+	w io.Writer
+}
+
+// NewRecorder returns a Recorder that keeps the most recent size recordings in
+// memory and writes each recording to w. Either can be disabled by providing a size
+// of 0 or a nil w.
+//
+//	rec := output.NewRecorder(100, nil)
+//	r := output.New(output.WithRecorder(rec))
+func NewRecorder(size int, w io.Writer) *Recorder {
+	if size < 0 {
+		size = 0
+	}
+
+	return &Recorder{
+		ring: make([]Recording, size),
+		w:    w,
+	}
+}
+
+// WithRecorder records each response sent by a Responder with rec.
+func WithRecorder(rec *Recorder) Option {
+	return WithAfterSend(rec.Record)
+}
+
+// Record captures a response. This is an AfterSendFunc, see OnAfterSend, and is
+// typically registered with WithRecorder.
+func (rec *Recorder) Record(res SendResult) {
+	//Nothing was sent.
+	if errors.Is(res.Err, ErrAlreadySent) {
+		return
+	}
+
+	j, err := json.Marshal(res.Payload)
+	if err != nil {
+		return
+	}
+
+	r := Recording{
+		ResponseCode: res.ResponseCode,
+		Payload:      j,
+		Time:         time.Now().UTC(),
+	}
+	if res.Request != nil {
+		r.Method = res.Request.Method
+		r.Path = res.Request.URL.Path
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if len(rec.ring) > 0 {
+		rec.ring[rec.next] = r
+		rec.next = (rec.next + 1) % len(rec.ring)
+		if rec.next == 0 {
+			rec.full = true
+		}
+	}
+
+	if rec.w != nil {
+		line, _ := json.Marshal(r)
+		rec.w.Write(append(line, '\n'))
+	}
+}
+
+// Recordings returns the recordings kept in memory, oldest first.
+func (rec *Recorder) Recordings() []Recording {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if !rec.full {
+		return append([]Recording(nil), rec.ring[:rec.next]...)
+	}
+
+	out := make([]Recording, 0, len(rec.ring))
+	out = append(out, rec.ring[rec.next:]...)
+	return append(out, rec.ring[:rec.next]...)
+}
+
+// ReadRecordings reads recordings written, as JSON lines, by a Recorder.
+func ReadRecordings(rd io.Reader) (recs []Recording, err error) {
+	sc := bufio.NewScanner(rd)
+	sc.Buffer(nil, 64*1024*1024)
+
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+
+		var r Recording
+		err = json.Unmarshal(sc.Bytes(), &r)
+		if err != nil {
+			return
+		}
+
+		recs = append(recs, r)
+	}
+
+	err = sc.Err()
+	return
+}
+
+// ReplayHandler returns a handler that serves recorded responses. A request is
+// responded to with the most recent recording with the same method and path, with
+// the recorded status code and Payload. A 404 error is sent if there is no matching
+// recording.
+//
+//	f, _ := os.Open("recordings.jsonl")
+//	recs, err := output.ReadRecordings(f)
+//	http.ListenAndServe(":8080", output.ReplayHandler(recs))
+func ReplayHandler(recs []Recording) http.Handler {
+	latest := make(map[string]Recording, len(recs))
+	for _, r := range recs {
+		latest[r.Method+" "+r.Path] = r
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r, ok := latest[req.Method+" "+req.URL.Path]
+		if !ok {
+			ErrorNotFound("No recorded response for this request.", w)
+			return
+		}
+
+		w.Header().Set("Content-Type", JSON.ContentType())
+		w.WriteHeader(r.ResponseCode)
+		w.Write(r.Payload)
+	})
+}
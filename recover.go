@@ -0,0 +1,66 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// errPanic is the error sent by RecoverMiddleware.
+var errPanic = errors.New("panic")
+
+// defaultPanicMsg is the Message sent by RecoverMiddleware.
+const defaultPanicMsg = "An unexpected error occured, please try again or contact an administrator."
+
+// RecoverMiddleware recovers panics in next and sends an HTTP status 500 error
+// Payload with the TypePanic message type so that clients never see an empty, or
+// raw, panic response. See Responder.RecoverMiddleware.
+func RecoverMiddleware(next http.Handler) http.Handler {
+	return std.RecoverMiddleware(next)
+}
+
+// RecoverMiddleware recovers panics in next, logs the panic and stack trace to the
+// Responder's logger, and sends an HTTP status 500 error Payload with the TypePanic
+// message type. The panic's value is only included in the response when debug is
+// enabled. If next already wrote a response, or part of one, before panicking the
+// panic is only logged.
+//
+// http.ErrAbortHandler is not recovered since it is used to intentionally abort a
+// response.
+func (r *Responder) RecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		//next is given the negotiated ResponseWriter so that a response it already
+		//started to write is known of if it panics.
+		w = Negotiate(w, req)
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			//Panics are always logged, regardless of debug, since they indicate a
+			//bug that needs to be fixed.
+			r.logger.Error("output.RecoverMiddleware: panic serving request", "method", req.Method, "path", req.URL.Path, "panic", rec, "stack", string(debug.Stack()))
+
+			//The error cannot be sent if next already wrote some, or all, of a
+			//response since it would be appended to that response.
+			if alreadySent(w) {
+				return
+			}
+
+			errType := errPanic
+			if r.debugging(w) {
+				errType = fmt.Errorf("%w: %v", errPanic, rec)
+			}
+
+			r.sendError(TypePanic, errType, defaultPanicMsg, nil, w, http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  func(w http.ResponseWriter, req *http.Request)
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "no panic",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				DataFound("a", w)
+			},
+			wantCode: http.StatusOK,
+			wantBody: `"Type":"dataFound"`,
+		},
+		{
+			name: "panic",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				panic("boom")
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: `"Type":"panic"`,
+		},
+		{
+			name: "panic after response",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				DataFound("a", w)
+				panic("boom")
+			},
+			wantCode: http.StatusOK,
+			wantBody: `"Type":"dataFound"`,
+		},
+		{
+			name: "panic after partial response",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				w.Write([]byte("partial"))
+				panic("boom")
+			},
+			wantCode: http.StatusOK,
+			wantBody: "partial",
+		},
+		{
+			name: "panic after response through negotiated writer",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				DataFound("a", Negotiate(w, req))
+				panic("boom")
+			},
+			wantCode: http.StatusOK,
+			wantBody: `"Type":"dataFound"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(WithLogWriter(io.Discard))
+			h := r.RecoverMiddleware(http.HandlerFunc(tt.handler))
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d", w.Code, tt.wantCode)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Fatalf("got body %q, want %q", w.Body.String(), tt.wantBody)
+			}
+			if strings.Contains(w.Body.String(), "panic") && tt.wantCode != http.StatusInternalServerError {
+				t.Fatalf("got body %q, want no panic response", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package output
+
+import (
+	"reflect"
+	"strings"
+)
+
+// RedactedValue replaces the value of redacted string fields, see WithRedactedFields.
+// Redacted fields of other types are set to their zero value.
+const RedactedValue = "[REDACTED]"
+
+// redactTag is the struct tag used to mark a field as redacted, `output:"redact"`.
+const redactTag = "output"
+
+// maxRedactDepth limits how deep Data is walked when redacting to protect against
+// cyclic data.
+const maxRedactDepth = 32
+
+// WithRedactedFields sets field names that are redacted from Data before it is
+// sent. Names are matched case-insensitively against struct field names, the names
+// given in json struct tags, and map keys. This is in addition to struct fields
+// tagged with `output:"redact"`, which are always redacted, and is useful for data
+// you do not control, such as maps or types from other packages.
+//
+// The Data provided is not modified, a copy is made when a field is redacted.
+func WithRedactedFields(names ...string) Option {
+	return func(r *Responder) {
+		if r.redactedFields == nil {
+			r.redactedFields = make(map[string]bool, len(names))
+		}
+
+		for _, n := range names {
+			r.redactedFields[strings.ToLower(n)] = true
+		}
+	}
+}
+
+// redact returns data with redacted fields masked.
+func (r *Responder) redact(data interface{}) interface{} {
+	if data == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(data)
+	if !r.mayRedact(v.Type()) {
+		return data
+	}
+
+	v, changed := r.redactValue(v, 0)
+	if !changed {
+		return data
+	}
+
+	return v.Interface()
+}
+
+// redactValue returns a copy of v with redacted fields masked. If nothing was
+// redacted v is returned as is, with changed set to false, so that data without
+// redacted fields is not copied.
+func (r *Responder) redactValue(v reflect.Value, depth int) (out reflect.Value, changed bool) {
+	if depth > maxRedactDepth || !r.mayRedact(v.Type()) {
+		return v, false
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v, false
+		}
+
+		elem, changed := r.redactValue(v.Elem(), depth+1)
+		if !changed {
+			return v, false
+		}
+
+		p := reflect.New(elem.Type())
+		p.Elem().Set(elem)
+		return p, true
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v, false
+		}
+
+		elem, changed := r.redactValue(v.Elem(), depth+1)
+		if !changed {
+			return v, false
+		}
+
+		i := reflect.New(v.Type()).Elem()
+		i.Set(elem)
+		return i, true
+
+	case reflect.Struct:
+		var c reflect.Value
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+
+			var fv reflect.Value
+			if r.redactedField(f) {
+				fv = redactedValue(f.Type)
+			} else {
+				var fieldChanged bool
+				fv, fieldChanged = r.redactValue(v.Field(i), depth+1)
+				if !fieldChanged {
+					continue
+				}
+			}
+
+			//Copy the struct the first time a field is changed.
+			if !c.IsValid() {
+				c = reflect.New(t).Elem()
+				c.Set(v)
+			}
+			c.Field(i).Set(fv)
+		}
+
+		if !c.IsValid() {
+			return v, false
+		}
+		return c, true
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return v, false
+		}
+
+		var c reflect.Value
+		for i := 0; i < v.Len(); i++ {
+			ev, elemChanged := r.redactValue(v.Index(i), depth+1)
+			if !elemChanged {
+				continue
+			}
+
+			//Copy the slice, or array, the first time an element is changed.
+			if !c.IsValid() {
+				if v.Kind() == reflect.Slice {
+					c = reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+					reflect.Copy(c, v)
+				} else {
+					c = reflect.New(v.Type()).Elem()
+					c.Set(v)
+				}
+			}
+			c.Index(i).Set(ev)
+		}
+
+		if !c.IsValid() {
+			return v, false
+		}
+		return c, true
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v, false
+		}
+
+		//Only the changed entries are held, the map is copied only if an entry
+		//was changed.
+		var (
+			changedKeys   []reflect.Value
+			changedValues []reflect.Value
+			iter          = v.MapRange()
+		)
+		for iter.Next() {
+			k, ev := iter.Key(), iter.Value()
+			if k.Kind() == reflect.String && r.redactedFields[strings.ToLower(k.String())] {
+				ev = redactedValue(v.Type().Elem())
+			} else if rv, elemChanged := r.redactValue(ev, depth+1); elemChanged {
+				ev = rv
+			} else {
+				continue
+			}
+
+			changedKeys = append(changedKeys, k)
+			changedValues = append(changedValues, ev)
+		}
+
+		if len(changedKeys) == 0 {
+			return v, false
+		}
+
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter = v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), iter.Value())
+		}
+		for i, k := range changedKeys {
+			c.SetMapIndex(k, changedValues[i])
+		}
+		return c, true
+
+	default:
+		return v, false
+	}
+}
+
+// mayRedact reports if a value of type t may have fields that are redacted. This
+// allows data without redacted fields, such as []byte or json.RawMessage, to be
+// skipped without walking it. The result is cached per type since it does not
+// change once the Responder is created.
+func (r *Responder) mayRedact(t reflect.Type) bool {
+	if cached, ok := r.redactTypes.Load(t); ok {
+		return cached.(bool)
+	}
+
+	may := r.typeMayRedact(t, make(map[reflect.Type]bool))
+	r.redactTypes.Store(t, may)
+	return may
+}
+
+// typeMayRedact reports if a value of type t may have fields that are redacted.
+// seen holds the types already being checked to protect against recursive types.
+func (r *Responder) typeMayRedact(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return r.typeMayRedact(t.Elem(), seen)
+
+	case reflect.Interface:
+		//The value's type is not known until the value is redacted.
+		return true
+
+	case reflect.Map:
+		if len(r.redactedFields) > 0 && t.Key().Kind() == reflect.String {
+			return true
+		}
+		return r.typeMayRedact(t.Elem(), seen)
+
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if r.redactedField(f) || r.typeMayRedact(f.Type, seen) {
+				return true
+			}
+		}
+		return false
+
+	default:
+		return false
+	}
+}
+
+// redactedField reports if the struct field f should be redacted.
+func (r *Responder) redactedField(f reflect.StructField) bool {
+	if f.Tag.Get(redactTag) == "redact" {
+		return true
+	}
+
+	if len(r.redactedFields) == 0 {
+		return false
+	}
+
+	if r.redactedFields[strings.ToLower(f.Name)] {
+		return true
+	}
+
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name != "" && r.redactedFields[strings.ToLower(name)]
+}
+
+// redactedValue returns the value used in place of a redacted value of type t.
+func redactedValue(t reflect.Type) reflect.Value {
+	v := reflect.New(t).Elem()
+
+	switch {
+	case t.Kind() == reflect.String:
+		v.SetString(RedactedValue)
+	case t.Kind() == reflect.Interface && reflect.TypeOf(RedactedValue).Implements(t):
+		v.Set(reflect.ValueOf(RedactedValue))
+	}
+
+	return v
+}
@@ -0,0 +1,182 @@
+package output
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type redactUser struct {
+	Name     string
+	Password string `output:"redact"`
+	Token    string `json:"token"`
+	Age      int
+}
+
+type redactNode struct {
+	Name     string
+	Password string `output:"redact"`
+	Next     *redactNode
+}
+
+type redactPlain struct {
+	Name string
+	Raw  json.RawMessage
+	Blob []byte
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []string
+		data   interface{}
+		want   interface{}
+	}{
+		{
+			name: "nil",
+			data: nil,
+			want: nil,
+		},
+		{
+			name: "tagged field",
+			data: redactUser{Name: "a", Password: "p", Token: "t", Age: 1},
+			want: redactUser{Name: "a", Password: RedactedValue, Token: "t", Age: 1},
+		},
+		{
+			name:   "named field by json tag",
+			fields: []string{"TOKEN"},
+			data:   redactUser{Name: "a", Token: "t"},
+			want:   redactUser{Name: "a", Password: RedactedValue, Token: RedactedValue},
+		},
+		{
+			name:   "named non-string field",
+			fields: []string{"age"},
+			data:   redactUser{Name: "a", Age: 1},
+			want:   redactUser{Name: "a", Password: RedactedValue},
+		},
+		{
+			name: "pointer",
+			data: &redactUser{Name: "a", Password: "p"},
+			want: &redactUser{Name: "a", Password: RedactedValue},
+		},
+		{
+			name: "slice",
+			data: []redactUser{{Name: "a", Password: "p"}, {Name: "b"}},
+			want: []redactUser{{Name: "a", Password: RedactedValue}, {Name: "b", Password: RedactedValue}},
+		},
+		{
+			name:   "map key",
+			fields: []string{"password"},
+			data:   map[string]interface{}{"password": "p", "name": "a"},
+			want:   map[string]interface{}{"password": RedactedValue, "name": "a"},
+		},
+		{
+			name: "interface",
+			data: map[string]interface{}{"user": redactUser{Name: "a", Password: "p"}},
+			want: map[string]interface{}{"user": redactUser{Name: "a", Password: RedactedValue}},
+		},
+		{
+			name: "recursive type",
+			data: &redactNode{Name: "a", Password: "p", Next: &redactNode{Name: "b", Password: "q"}},
+			want: &redactNode{Name: "a", Password: RedactedValue, Next: &redactNode{Name: "b", Password: RedactedValue}},
+		},
+		{
+			name:   "bytes",
+			fields: []string{"name"},
+			data:   redactPlain{Raw: json.RawMessage(`{"name":"a"}`), Blob: []byte("name")},
+			want:   redactPlain{Name: RedactedValue, Raw: json.RawMessage(`{"name":"a"}`), Blob: []byte("name")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(WithRedactedFields(tt.fields...))
+
+			got := r.redact(tt.data)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactUnchanged(t *testing.T) {
+	m := map[string]interface{}{"name": "a"}
+	raw := json.RawMessage(`{"password":"p"}`)
+
+	tests := []struct {
+		name   string
+		fields []string
+		data   interface{}
+	}{
+		{
+			name:   "map without redacted keys",
+			fields: []string{"password"},
+			data:   m,
+		},
+		{
+			name:   "raw message",
+			fields: []string{"password"},
+			data:   raw,
+		},
+		{
+			name: "struct without redacted fields",
+			data: &redactPlain{Name: "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(WithRedactedFields(tt.fields...))
+
+			//Data without redacted fields is returned as is, not copied.
+			got := r.redact(tt.data)
+			if reflect.ValueOf(got).Pointer() != reflect.ValueOf(tt.data).Pointer() {
+				t.Fatalf("data was copied")
+			}
+		})
+	}
+}
+
+func TestMayRedact(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []string
+		data   interface{}
+		want   bool
+	}{
+		{"string", nil, "a", false},
+		{"bytes", []string{"name"}, []byte("a"), false},
+		{"raw message", []string{"name"}, json.RawMessage(`{}`), false},
+		{"plain struct", nil, redactPlain{}, false},
+		{"named field", []string{"name"}, redactPlain{}, true},
+		{"tagged field", nil, redactUser{}, true},
+		{"recursive type", nil, redactNode{}, true},
+		{"map without names", nil, map[string]int{}, false},
+		{"map with names", []string{"a"}, map[string]int{}, true},
+		{"interface", nil, []interface{}{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(WithRedactedFields(tt.fields...))
+
+			if got := r.mayRedact(reflect.TypeOf(tt.data)); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkRedact(b *testing.B) {
+	r := New(WithRedactedFields("password"))
+	data := make([]redactPlain, 100)
+	for i := range data {
+		data[i] = redactPlain{Name: "a", Blob: make([]byte, 1024)}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.redact(data)
+	}
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// RedirectPayload is the Data sent by Redirect and RedirectJSON.
+type RedirectPayload struct {
+	//URL is where the client should go.
+	URL string
+}
+
+// Redirect is used to redirect the client to a different URL. This is similar to
+// http.Redirect, setting the Location header and a 3xx HTTP status code, but the
+// body is a Payload with the URL in Data so that the response format is the same as
+// other responses.
+//
+// Relative targets are resolved against req's URL. ErrInvalidResponseCode is returned
+// if responseCode is not a 3xx status code.
+func Redirect(w http.ResponseWriter, req *http.Request, target string, responseCode int, opts ...SendOption) (err error) {
+	err = std.Redirect(w, req, target, responseCode, opts...)
+	return
+}
+
+// RedirectJSON is used to tell the client to go to a different URL when the client
+// cannot, or should not, follow a 3xx redirect automatically. For example, requests
+// made with XHR or fetch follow redirects transparently so client-side code never
+// sees the new URL. This returns an HTTP status 200, with the URL in Data, so that
+// client-side code can handle the redirect itself.
+func RedirectJSON(target string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.RedirectJSON(target, w, opts...)
+	return
+}
+
+// Redirect sends a 3xx redirect. See the package-level Redirect.
+func (r *Responder) Redirect(w http.ResponseWriter, req *http.Request, target string, responseCode int, opts ...SendOption) (err error) {
+	if responseCode < http.StatusMultipleChoices || responseCode > 399 {
+		r.log(w, "output.Redirect: invalid HTTP response code provided", "status", responseCode)
+
+		err = ErrInvalidResponseCode
+		return
+	}
+
+	//Resolve relative URLs, like http.Redirect does, so the client gets an
+	//unambiguous location.
+	if u, parseErr := url.Parse(target); parseErr == nil && u.Scheme == "" && u.Host == "" && req != nil {
+		target = req.URL.ResolveReference(u).String()
+	}
+
+	opts = append([]SendOption{WithHeader("Location", target)}, opts...)
+
+	rp := RedirectPayload{
+		URL: target,
+	}
+
+	err = r.buildAndSend(true, TypeRedirect, rp, ErrorPayload{}, w, responseCode, opts...)
+	return
+}
+
+// RedirectJSON sends a 200 with a URL to redirect to. See the package-level
+// RedirectJSON.
+func (r *Responder) RedirectJSON(target string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	rp := RedirectPayload{
+		URL: target,
+	}
+
+	err = r.buildAndSend(true, TypeRedirect, rp, ErrorPayload{}, w, r.successCode, opts...)
+	return
+}
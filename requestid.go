@@ -0,0 +1,110 @@
+package output
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// HeaderRequestID is the header used to receive and send a request's ID.
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength is the longest request ID accepted from a client. Longer IDs
+// are replaced with a generated ID.
+const maxRequestIDLength = 128
+
+// requestIDKey is the context key the request ID is stored under.
+type requestIDKey struct{}
+
+// RequestIDMiddleware assigns an ID to each request so that clients can quote the
+// ID in support tickets and the ID can be used to find the request in logs. The ID
+// is taken from the request's X-Request-ID header, if valid, so that IDs assigned by
+// a proxy are propagated. Otherwise, a random ID is generated.
+//
+// The ID is stored in the request's context, see RequestIDFrom, and set in the
+// response's X-Request-ID header. The ID is included as the Payload's RequestID when
+// responses are sent with SendCtx, ErrorCtx, or with a ResponseWriter that carries
+// the request (see Negotiate).
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		id := req.Header.Get(HeaderRequestID)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(HeaderRequestID, id)
+
+		ctx := context.WithValue(req.Context(), requestIDKey{}, id)
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// RequestIDFrom returns the request ID stored in ctx by RequestIDMiddleware. A blank
+// string is returned if ctx does not have a request ID.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestID sets the Payload's RequestID.
+func WithRequestID(id string) SendOption {
+	return func(o *sendOptions) {
+		o.requestID = id
+	}
+}
+
+// SendCtx is similar to Send but uses ctx as the context of the request being
+// responded to. This sets the Payload's RequestID from ctx, see
+// RequestIDMiddleware, DurationMS, see TimingMiddleware, and the trace details, see
+// WithTracer.
+func SendCtx(ctx context.Context, p Payload, w http.ResponseWriter, responseCode int, opts ...SendOption) (err error) {
+	err = std.SendCtx(ctx, p, w, responseCode, opts...)
+	return
+}
+
+// ErrorCtx is similar to Error but uses ctx as the context of the request being
+// responded to. See SendCtx.
+func ErrorCtx(ctx context.Context, errType error, errMsg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorCtx(ctx, errType, errMsg, w, opts...)
+	return
+}
+
+// SendCtx sends a Payload with the request ID from ctx. See the package-level
+// SendCtx.
+func (r *Responder) SendCtx(ctx context.Context, p Payload, w http.ResponseWriter, responseCode int, opts ...SendOption) (err error) {
+	opts = append([]SendOption{withContext(ctx)}, opts...)
+	err = r.Send(p, w, responseCode, opts...)
+	return
+}
+
+// ErrorCtx sends an error with the request ID from ctx. See the package-level
+// ErrorCtx.
+func (r *Responder) ErrorCtx(ctx context.Context, errType error, errMsg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	opts = append([]SendOption{withContext(ctx)}, opts...)
+	err = r.Error(errType, errMsg, w, opts...)
+	return
+}
+
+// validRequestID reports if a request ID provided by a client can be used. IDs must
+// be printable ASCII, without spaces, so they are safe to log and send in headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRequestID returns a random request ID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,919 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Responder sends responses using its own configuration. This allows multiple
+// services, or parts of an application, to use different output behavior without
+// relying on package-level state.
+//
+// The package-level funcs (Success, Error, etc.) use a default Responder that is
+// created with New() and no options.
+type Responder struct {
+	//successCode is the HTTP status code used by Success and related funcs.
+	successCode int
+
+	//errorCode is the HTTP status code used by Error and related funcs.
+	errorCode int
+
+	//timestampFormat is the layout used to format the Datetime field of each
+	//Payload. The time is always in the UTC timezone.
+	timestampFormat string
+
+	//omitDatetime and datetimeField alter the Datetime field of each Payload. See
+	//WithOmitDatetime and WithDatetimeField.
+	omitDatetime  bool
+	datetimeField string
+
+	//keyCase is the casing of each Payload's keys. See WithKeyCase.
+	keyCase KeyCase
+
+	//fieldNames are the keys used for fields of each Payload, keyed by field
+	//name. See WithFieldNames.
+	fieldNames map[string]string
+
+	//keys is built from the options that alter how each Payload's keys are
+	//encoded.
+	keys *envelopeKeys
+
+	//apiVersion and defaultHeader are set on every response. See WithAPIVersion
+	//and WithDefaultHeader.
+	apiVersion    string
+	defaultHeader http.Header
+
+	//defaultCookies and cookieFuncs are set on every response. See
+	//WithDefaultCookie and WithCookieFunc.
+	defaultCookies []*http.Cookie
+	cookieFuncs    []CookieFunc
+
+	//defaultMeta is included in the Meta of every Payload. See WithDefaultMeta.
+	defaultMeta map[string]interface{}
+
+	//sequence causes a sequence number, from seq, to be included in each Payload.
+	//See WithSequence.
+	sequence bool
+	seq      atomic.Uint64
+
+	//logger is where diagnostic logging is written when debug is enabled.
+	logger Logger
+
+	//debug enables diagnostic logging. This is atomic so that debug can be toggled,
+	//for example from an admin endpoint, while responses are being sent.
+	debug atomic.Bool
+
+	//debugHeader and debugToken enable debug for a single response when the
+	//request's header matches the token. See WithDebugToken.
+	debugHeader string
+	debugToken  string
+
+	//verboseErrors causes the chain of wrapped errors to be included in error
+	//responses. See WithVerboseErrors.
+	verboseErrors bool
+
+	//stackTraces causes a stack trace to be included in error responses when
+	//debug is enabled. See WithStackTraces.
+	stackTraces bool
+
+	//messageTypes is the list of message types that are allowed to be used when
+	//strictMessageTypes is enabled.
+	messageTypes map[MessageType]struct{}
+
+	//strictMessageTypes causes an error to be returned when a message type not in
+	//messageTypes is used.
+	strictMessageTypes bool
+
+	//refuseUndefinedMessageTypes causes responses using a message type not in
+	//messageTypes to not be sent when strictMessageTypes is enabled.
+	refuseUndefinedMessageTypes bool
+
+	//problemDetails causes error responses to be sent as RFC 9457 problem details
+	//instead of a Payload.
+	problemDetails bool
+
+	//jsonAPI causes responses to be sent as JSON:API documents instead of a
+	//Payload. jsonAPIResource is used to determine the type and id of Data. See
+	//WithJSONAPI.
+	jsonAPI         bool
+	jsonAPIResource JSONAPIResourceFunc
+
+	//prettyJSON and prettyParam cause JSON responses to be indented, always or
+	//when requested. See WithPrettyJSON and WithPrettyParam.
+	prettyJSON  bool
+	prettyParam string
+
+	//escapeHTML causes <, >, and & to be escaped in JSON responses. See
+	//WithEscapeHTML.
+	escapeHTML bool
+
+	//canonicalJSON causes JSON responses to be encoded the same way every time.
+	//See WithCanonicalJSON.
+	canonicalJSON bool
+
+	//signingKey is used to sign response bodies. See WithSigningKey.
+	signingKey []byte
+
+	//jwsSigner and jwsSerialization are used to wrap responses in a JWS. See
+	//WithJWS.
+	jwsSigner        JWSSigner
+	jwsSerialization JWSSerialization
+
+	//jweKeyFunc returns the key used to encrypt Data. See WithEncryptedData.
+	jweKeyFunc JWEKeyFunc
+
+	//redactedFields are names of fields redacted from Data. See
+	//WithRedactedFields.
+	redactedFields map[string]bool
+
+	//redactTypes caches, by reflect.Type, if a value of the type may have fields
+	//that are redacted. See mayRedact.
+	redactTypes sync.Map
+
+	//maxResponseSize is the maximum size of an encoded response. See
+	//WithMaxResponseSize.
+	maxResponseSize      int
+	sendResponseTooLarge bool
+
+	//truncateItems is the maximum number of items in list Data. See
+	//WithTruncation.
+	truncateItems  int
+	truncateCursor CursorFunc
+
+	//hal causes responses to be sent as HAL documents instead of a Payload. See
+	//WithHAL.
+	hal bool
+
+	//encoders are the Encoders that responses can be encoded with, in order of
+	//preference. The first Encoder is the default.
+	encoders []Encoder
+
+	//paginationLinks causes Link headers to be sent with paginated responses.
+	paginationLinks bool
+
+	//errorMappings are used to choose the HTTP status code and message type based
+	//on the error provided to Error. See MapError.
+	errorMappings []errorMapping
+
+	//errorCatalog is used to populate an ErrorPayload based on the error being
+	//sent. See RegisterError.
+	errorCatalog []errorCatalogEntry
+
+	//docsBaseURL is used to build an ErrorPayload's DocsURL from its Code when the
+	//error catalog does not provide a DocsURL.
+	docsBaseURL string
+
+	//maintenance causes all responses to be replaced with a 503 maintenance error.
+	//See Maintenance.
+	maintenance           bool
+	maintenanceMsg        string
+	maintenanceRetryAfter time.Duration
+
+	//tracer is used to embed trace details in responses. See WithTracer.
+	tracer Tracer
+
+	//metrics receives metrics about each response sent. See WithMetrics.
+	metrics MetricsSink
+
+	//beforeSend and afterSend are called before and after each response is sent.
+	//See OnBeforeSend and OnAfterSend.
+	beforeSend []BeforeSendFunc
+	afterSend  []AfterSendFunc
+
+	//marshalError are called when a response cannot be encoded. See
+	//OnMarshalError.
+	marshalError []MarshalErrorFunc
+
+	//contentLength causes the Content-Length header to be set on every response.
+	//See WithContentLength.
+	contentLength bool
+
+	//etags causes an ETag to be calculated for successful responses. See
+	//WithETags.
+	etags bool
+
+	//cachePolicies are the CachePolicy for each message type. See
+	//WithCachePolicy.
+	cachePolicies map[MessageType]CachePolicy
+
+	//compressionMinSize is the size of a response above which it is compressed.
+	//See WithCompression.
+	compressionMinSize int
+	compressors        []Compressor
+
+	//envelopeHeaders causes the Payload's fields to be sent as headers with raw
+	//responses. See WithEnvelopeHeaders.
+	envelopeHeaders bool
+
+	//schemas are the JSON Schemas that Data must match for each message type, and
+	//schemaValidation causes Data to be validated. See RegisterSchema.
+	schemas          map[MessageType]*JSONSchema
+	schemaValidation bool
+
+	//dataTypes are the types of Data sent with each message type. See
+	//RegisterDataType.
+	dataTypes map[MessageType]reflect.Type
+
+	//chaos configures fault injection. See WithChaos.
+	chaos *ChaosConfig
+
+	//samplePercent is the percentage of responses summarized to sampleSink. See
+	//WithSampling.
+	samplePercent float64
+	sampleSink    SampleSink
+
+	//mu protects fields that can be modified after New, such as errorMappings and
+	//messageTypes.
+	mu sync.RWMutex
+}
+
+// defaultTimestampFormat is the layout used for the Datetime field of each Payload.
+// Since the time is in the UTC timezone, the offset is formatted as "Z".
+const defaultTimestampFormat = TimestampRFC3339Milli
+
+// std is the default Responder used by the package-level funcs.
+var std = New()
+
+// Option is used to configure a Responder when calling New.
+type Option func(*Responder)
+
+// WithSuccessCode sets the HTTP status code returned by Success and related funcs.
+func WithSuccessCode(code int) Option {
+	return func(r *Responder) {
+		r.successCode = code
+	}
+}
+
+// WithErrorCode sets the HTTP status code returned by Error and related funcs.
+func WithErrorCode(code int) Option {
+	return func(r *Responder) {
+		r.errorCode = code
+	}
+}
+
+// WithTimestampFormat sets the format of the Datetime field of each Payload. Use one
+// of the Timestamp formats, such as TimestampRFC3339 or TimestampUnixMilli, or any
+// layout as used by time.Format.
+func WithTimestampFormat(layout string) Option {
+	return func(r *Responder) {
+		r.timestampFormat = layout
+	}
+}
+
+// Logger is used for diagnostic logging. Messages are logged with args as
+// alternating keys and values, such as the message type and HTTP status code.
+// *slog.Logger satisfies Logger; loggers such as zerolog or logrus can be used with
+// a small adapter.
+type Logger interface {
+	//Info logs diagnostic messages. Diagnostic messages are only logged when
+	//debug is enabled.
+	Info(msg string, args ...interface{})
+
+	//Error logs messages that are logged regardless of debug, such as panics
+	//recovered by RecoverMiddleware.
+	Error(msg string, args ...interface{})
+}
+
+// WithLogger sets the logger used for diagnostic logging.
+func WithLogger(l Logger) Option {
+	return func(r *Responder) {
+		r.logger = l
+	}
+}
+
+// WithLogWriter causes diagnostic logging to be written to w, as text formatted by
+// slog.TextHandler. This is useful for capturing diagnostics in tests.
+func WithLogWriter(w io.Writer) Option {
+	return func(r *Responder) {
+		r.logger = slog.New(slog.NewTextHandler(w, nil))
+	}
+}
+
+// WithDebug turns diagnostic logging on or off.
+func WithDebug(b bool) Option {
+	return func(r *Responder) {
+		r.debug.Store(b)
+	}
+}
+
+// WithProblemDetails causes error responses to be sent as RFC 9457 problem details,
+// with the application/problem+json content type, instead of as a Payload. This only
+// applies when the response is encoded with the JSON Encoder. See ProblemDetails.
+func WithProblemDetails(b bool) Option {
+	return func(r *Responder) {
+		r.problemDetails = b
+	}
+}
+
+// WithEncoders sets the Encoders that responses can be encoded with, in order of
+// preference. The first Encoder is used unless a different one is chosen based on
+// the request's Accept header (see Negotiate). If no Encoders are provided, JSON is
+// used.
+func WithEncoders(encs ...Encoder) Option {
+	return func(r *Responder) {
+		if len(encs) == 0 {
+			encs = []Encoder{JSON}
+		}
+
+		r.encoders = encs
+	}
+}
+
+// WithPaginationLinks causes RFC 8288 Link headers to be sent with responses from
+// DataFoundPaginated. The links are built from the request's URL so the
+// ResponseWriter must carry the request, see Negotiate.
+func WithPaginationLinks(b bool) Option {
+	return func(r *Responder) {
+		r.paginationLinks = b
+	}
+}
+
+// New returns a Responder configured with the provided options. Any option not
+// provided uses the same default as the package-level funcs.
+func New(opts ...Option) *Responder {
+	r := &Responder{
+		successCode:     http.StatusOK,
+		errorCode:       http.StatusInternalServerError,
+		timestampFormat: defaultTimestampFormat,
+		logger:          slog.Default(),
+		encoders:        []Encoder{JSON},
+		escapeHTML:      true,
+		compressors:     []Compressor{Gzip},
+		messageTypes: map[MessageType]struct{}{
+			TypeError:     {},
+			TypeInsertOK:  {},
+			TypeUpdateOK:  {},
+			TypeDeleteOK:  {},
+			TypeDataFound: {},
+			TypeCreated:   {},
+			TypeAccepted:  {},
+			TypeRedirect:  {},
+
+			TypeMethodNotAllowed: {},
+			TypeMaintenance:      {},
+			TypeTimeout:          {},
+			TypePanic:            {},
+			TypeResponseTooLarge: {},
+		},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	r.keys = r.newEnvelopeKeys()
+
+	return r
+}
+
+// Debug turns debug logging on or off. This is safe to call while responses are
+// being sent and only affects this Responder.
+func (r *Responder) Debug(b bool) {
+	r.debug.Store(b)
+}
+
+// timestamp returns the current time formatted for the Datetime field.
+func (r *Responder) timestamp() string {
+	return formatTimestamp(time.Now(), r.timestampFormat)
+}
+
+// log writes a diagnostic log message, with args as alternating keys and values,
+// if debug is enabled for the response being sent to w. w may be nil.
+func (r *Responder) log(w http.ResponseWriter, msg string, args ...interface{}) {
+	if !r.debugging(w) {
+		return
+	}
+
+	r.logger.Info(msg, args...)
+}
+
+// newPayload builds a Payload from the provided ok, msgType, msgData, and errData.
+func (r *Responder) newPayload(ok bool, msgType MessageType, msgData interface{}, errData ErrorPayload) Payload {
+	//Note that Data or ErrorData will be removed from JSON if they are empty (per
+	//struct tags on fields).
+	return Payload{
+		OK:        ok,
+		Type:      string(msgType),
+		Data:      msgData,
+		ErrorData: errData,
+		Datetime:  r.timestamp(),
+	}
+}
+
+// buildAndSend builds a Payload from the provided ok, msgType, msgData, and errData
+// and then calls send().
+func (r *Responder) buildAndSend(ok bool, msgType MessageType, msgData interface{}, errData ErrorPayload, w http.ResponseWriter, responseCode int, opts ...SendOption) (err error) {
+	//Payloads are reused since one is built for every response.
+	p := getPayload()
+	defer putPayload(p)
+	*p = r.newPayload(ok, msgType, msgData, errData)
+
+	//Send the response.
+	err = r.send(p, w, responseCode, opts...)
+	return
+}
+
+// encoder returns the Encoder to use for a response. If w carries a request, see
+// Negotiate, the Encoder is chosen based on the request's Accept header.
+func (r *Responder) encoder(w http.ResponseWriter) Encoder {
+	req := requestFrom(w)
+	if req == nil || len(r.encoders) == 1 {
+		return r.encoders[0]
+	}
+
+	//The response differs based on the Accept header so caches need to know.
+	addVary(w.Header(), "Accept")
+
+	return negotiate(req.Header.Get("Accept"), r.encoders)
+}
+
+// addVary adds field to the Vary header unless it is already listed.
+func addVary(h http.Header, field string) {
+	for _, v := range h.Values("Vary") {
+		for _, f := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(f), field) {
+				return
+			}
+		}
+	}
+
+	h.Add("Vary", field)
+}
+
+// send handles actually sending the response.
+func (r *Responder) send(p *Payload, w http.ResponseWriter, responseCode int, opts ...SendOption) (err error) {
+	//Record metrics, and call hooks, once the response has been written. A closure
+	//is used since the Payload and response code can be altered below.
+	var (
+		start = time.Now()
+		size  int
+	)
+	defer func() {
+		r.runAfterSend(w, SendResult{
+			Payload:      *p,
+			Request:      requestFrom(w),
+			ResponseCode: responseCode,
+			Duration:     time.Since(start),
+			Size:         size,
+			Err:          err,
+		})
+	}()
+
+	//Don't send a second response, the status code and body would be appended to,
+	//or mangle, the first response.
+	if alreadySent(w) {
+		r.log(w, "output.send: response already sent", "type", p.Type)
+		err = ErrAlreadySent
+		return
+	}
+
+	//Replace whatever was going to be sent if maintenance mode is enabled.
+	if r.inMaintenance(w, p, &responseCode, &opts) {
+		r.log(w, "output.send: maintenance mode enabled, sending maintenance response")
+	}
+
+	//Replace whatever was going to be sent if the request's deadline has passed
+	//since the client, or a proxy, has most likely given up on the response.
+	if r.timedOut(w, p, &responseCode, &opts) {
+		r.log(w, "output.send: request deadline exceeded, sending timeout response")
+	}
+
+	//Inject faults, for testing clients' error handling, when enabled.
+	if r.injectFault(w, p, &responseCode, &opts) {
+		r.log(w, "output.send: injected fault", "fault", w.Header().Get(HeaderChaos))
+	}
+
+	o := applySendOptions(opts)
+	responseCode = o.apply(p, responseCode)
+
+	//Catch changes to the shape of Data before clients do, when enabled.
+	err = r.checkSchema(p, w, o)
+	if err != nil {
+		return
+	}
+
+	//Data read from a reader is streamed into the encoded Payload when possible,
+	//otherwise it is read in full and handled like any other Data.
+	var streaming bool
+	if o.stream != nil && !o.raw {
+		streaming, err = r.prepareStream(p, w, o.stream)
+		if err != nil {
+			r.log(w, "output.send: could not read data", "error", err)
+			return
+		}
+	}
+
+	//Use the context from the request, if available, so that every response
+	//includes the request ID, duration, and trace details without needing to use
+	//SendCtx or ErrorCtx.
+	ctx := o.ctx
+	if req := requestFrom(w); ctx == nil && req != nil {
+		ctx = req.Context()
+	}
+	if ctx != nil && p.RequestID == "" {
+		p.RequestID = RequestIDFrom(ctx)
+	}
+	if ctx != nil {
+		if reqStart, ok := RequestStartFrom(ctx); ok {
+			p.DurationMS = durationMS(reqStart)
+
+			//Metrics and hooks use the same start time so they cover handling the
+			//request, not just sending the response.
+			start = reqStart
+		}
+	}
+	r.trace(ctx, p, w, responseCode)
+
+	r.runBeforeSend(p, w)
+	r.setEnvelopeKeys(p)
+	r.setDefaultHeaders(p, w.Header())
+	r.setCookies(w, o)
+	r.setCacheControl(w, p, o)
+	r.setDefaultMeta(p)
+	p.Sequence = r.nextSequence()
+	if o.raw {
+		r.setEnvelopeHeaders(w, p)
+	}
+
+	if !o.raw {
+		r.truncate(p)
+	}
+	p.Data = r.redact(p.Data)
+
+	//Don't send the response if the client already has it. The ETag is calculated
+	//from the Data before it is encrypted since encryption is not deterministic.
+	if etag := r.etag(p, o, responseCode, requestFrom(w)); etag != "" {
+		w.Header().Set("ETag", etag)
+
+		if notModified(requestFrom(w), etag) {
+			//The 304 must have the same Vary headers as the response it replaces
+			//so that caches store it for the same requests.
+			if o.stream == nil || !o.raw {
+				r.encoder(w)
+				if r.compressionMinSize > 0 && !streaming {
+					addVary(w.Header(), "Accept-Encoding")
+				}
+			}
+
+			responseCode = http.StatusNotModified
+			o.setHeaders(w)
+			w.WriteHeader(responseCode)
+			return
+		}
+	}
+
+	//Encrypt Data, if configured, before anything is sent. Nothing is sent if the
+	//Data cannot be encrypted since it would otherwise be exposed.
+	if !o.raw {
+		err = r.encryptData(p, w)
+		if err != nil {
+			r.log(w, "output.send: could not encrypt data", "error", err)
+			return
+		}
+	}
+
+	//Some status codes cannot have a body so only the headers are sent. Data
+	//should not be provided with these status codes since it will not be sent.
+	if !bodyAllowed(responseCode) {
+		o.setHeaders(w)
+		w.WriteHeader(responseCode)
+
+		if p.Data != nil {
+			r.log(w, "output.send: data provided with status code that cannot have a body", "status", responseCode)
+			err = ErrBodyNotAllowed
+		}
+		return
+	}
+
+	//Raw content read from a reader is sent as is.
+	if o.stream != nil && o.raw {
+		err = r.streamRaw(w, o.stream, o, responseCode)
+		return
+	}
+
+	enc := r.encoder(w)
+
+	//Build the body to send back. Only the Data is sent for raw responses.
+	//Responses are converted to JSON:API or HAL documents, or error responses to
+	//problem details, if the Responder is configured to do so.
+	var (
+		body        interface{} = p
+		contentType             = enc.ContentType()
+	)
+	switch {
+	case o.raw:
+		body = p.Data
+	case r.jsonAPI && enc == JSON:
+		body, err = r.newJSONAPIDocument(p, responseCode)
+		if err != nil {
+			return
+		}
+		contentType = jsonAPIContentType
+	case r.hal && enc == JSON:
+		body = newHALDocument(p)
+		contentType = halContentType
+	case !p.OK && r.problemDetails && enc == JSON:
+		body = newProblemDetails(p, responseCode, requestFrom(w))
+		contentType = "application/problem+json; charset=UTF-8"
+	}
+
+	//Most responses are encoded directly to the ResponseWriter to avoid holding a
+	//copy of every response in memory. The headers, and response code, must be
+	//written first since the body may be partially written as it is encoded.
+	if !r.buffered(w, contentType, streaming) && !o.truncateBody {
+		w.Header().Set("Content-Type", contentType)
+		o.setHeaders(w)
+
+		hw := &headerWriter{w: w, code: responseCode}
+		bw := getBufWriter(hw)
+		defer putBufWriter(bw)
+
+		err = enc.Encode(bw, body)
+		if err != nil && !hw.wrote {
+			responseCode = http.StatusInternalServerError
+			r.marshalFailed(w, p, err)
+			return
+		}
+
+		if flushErr := bw.Flush(); err == nil {
+			err = flushErr
+		}
+		hw.writeHeader()
+		size = hw.n
+		return
+	}
+
+	b := getBuffer()
+	defer putBuffer(b)
+
+	err = enc.Encode(b, body)
+	if err != nil {
+		responseCode = http.StatusInternalServerError
+		r.marshalFailed(w, p, err)
+		return
+	}
+
+	out := b.Bytes()
+	if isJSON(contentType) {
+		if r.canonicalJSON {
+			out = canonicalJSON(out)
+		}
+		if !r.escapeHTML {
+			out = unescapeHTML(out)
+		}
+		if r.pretty(w) && !r.canonicalJSON {
+			out = indentJSON(out)
+		}
+	}
+
+	//Wrap the response in a JWS. Nothing is sent if the response cannot be signed
+	//since the client would reject, or worse trust, an unsigned response.
+	if r.jwsSigner != nil && isJSON(contentType) {
+		out, contentType, err = r.jws(out, contentType)
+		if err != nil {
+			r.log(w, "output.send: could not sign JWS", "error", err)
+			return
+		}
+	}
+
+	//Don't send responses that are too large, the client would most likely time out
+	//before receiving the response anyway. An error is sent in its place, if the
+	//Responder is configured to do so, but ErrResponseTooLarge is still returned.
+	if r.tooLarge(w, out) {
+		err = ErrResponseTooLarge
+		if r.sendResponseTooLarge {
+			responseCode = http.StatusInternalServerError
+			size = r.sendTooLarge(p, w, enc)
+		}
+		return
+	}
+
+	//Set the headers. This must be done before the response code is written
+	//otherwise they will be ignored.
+	w.Header().Set("Content-Type", contentType)
+	o.setHeaders(w)
+	r.sign(w.Header(), out)
+	if !streaming {
+		var coding string
+		out, coding = r.compress(w, out)
+		if coding != "" {
+			w.Header().Set("Content-Encoding", coding)
+			w.Header().Del("Content-Length")
+		}
+	}
+	head := isHead(w)
+	if (r.contentLength || head || o.truncateBody) && !streaming {
+		w.Header().Set("Content-Length", strconv.Itoa(len(out)))
+	}
+	if o.truncateBody && !streaming {
+		out = out[:len(out)/2]
+	}
+
+	//Set the response code.
+	w.WriteHeader(responseCode)
+
+	//Only the headers are sent in response to HEAD requests.
+	if head {
+		return
+	}
+
+	//Send back the encoded response.
+	if streaming {
+		err = r.writeStream(w, out, o.stream, o.truncateBody)
+		return
+	}
+	size, _ = w.Write(out)
+	return
+}
+
+// Send is used to send any response, with any payload, and any response code. See
+// the package-level Send for details.
+func (r *Responder) Send(p Payload, w http.ResponseWriter, responseCode int, opts ...SendOption) (err error) {
+	//Do some validation since the payload is constructed manually.
+	if strings.TrimSpace(p.Datetime) == "" {
+		p.Datetime = r.timestamp()
+	}
+
+	//If ErrorData or Errors is provided, OK must be false. Data can still be
+	//provided when errors occur though (see ErrorWithID()).
+	if !p.ErrorData.IsZero() || len(p.Errors) > 0 {
+		p.OK = false
+	}
+
+	//Make sure a response code was provided.
+	if responseCode < http.StatusContinue {
+		r.log(w, "output.Send: invalid HTTP response code provided", "status", responseCode)
+
+		err = ErrInvalidResponseCode
+		return
+	}
+
+	//Make sure a message type was provided. Message type is used to handle responses
+	//by the client so this field is required.
+	//
+	//If a type wasn't provided, use the HTTP status code. The code and status
+	//message are concatted together so hopefully help developers identify that they
+	//did not provide a status code manually.
+	if strings.TrimSpace(p.Type) == "" {
+		p.Type = fmt.Sprintf("%d-%s", responseCode, http.StatusText(responseCode))
+
+		r.log(w, "output.Send: payload has no message type, defaulting to type based on HTTP response code", "status", responseCode, "type", p.Type)
+	}
+
+	//We could do some checking if a 4xx code was provided if ErrorData was also
+	//provided, but we would rather not enforce a set of status codes since each
+	//application that uses this package may have different ideas of how to use
+	//ErrorData and what applicable code to return.
+
+	//Send the response.
+	err = r.send(&p, w, responseCode, opts...)
+	return
+}
+
+// Success sends a successful response using the Responder's configuration. See the
+// package-level Success for details.
+//
+// If strict message types are enforced and msgType was not defined, the response is
+// still sent but an error wrapping ErrUndefinedMessageType is returned, unless the
+// Responder refuses to send undefined message types.
+func (r *Responder) Success(msgType MessageType, data interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	//Check the message type actually sent, which may have been changed with
+	//WithType.
+	checkType := msgType
+	if o := applySendOptions(opts); o.msgType != "" {
+		checkType = o.msgType
+	}
+
+	refuse, typeErr := r.checkMessageType(checkType, w)
+	if refuse {
+		err = typeErr
+		return
+	}
+
+	err = r.buildAndSend(true, msgType, data, ErrorPayload{}, w, r.successCode, opts...)
+	if err != nil {
+		return
+	}
+
+	err = typeErr
+	return
+}
+
+// InsertOK sends the ID of just inserted data. See the package-level InsertOK.
+func (r *Responder) InsertOK(id int64, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.Success(TypeInsertOK, id, w, opts...)
+	return
+}
+
+// InsertOKWithData sends data after a successful insert. See the package-level
+// InsertOKWithData.
+func (r *Responder) InsertOKWithData(data interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.Success(TypeInsertOK, data, w, opts...)
+	return
+}
+
+// UpdateOK reports a successful update. See the package-level UpdateOK.
+func (r *Responder) UpdateOK(w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.Success(TypeUpdateOK, nil, w, opts...)
+	return
+}
+
+// UpdateOKWithData sends data after a successful update. See the package-level
+// UpdateOKWithData.
+func (r *Responder) UpdateOKWithData(data interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.Success(TypeUpdateOK, data, w, opts...)
+	return
+}
+
+// DataFound sends data that was looked up. See the package-level DataFound.
+func (r *Responder) DataFound(data interface{}, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.Success(TypeDataFound, data, w, opts...)
+	return
+}
+
+// Error sends an error response using the Responder's configuration. See the
+// package-level Error for details.
+func (r *Responder) Error(errType error, errMsg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	code, msgType := r.mapError(errType)
+	err = r.sendError(msgType, errType, errMsg, nil, w, code, opts...)
+	return
+}
+
+// newErrorPayload builds an ErrorPayload from the provided error and message. The
+// error's code, default message, and documentation URL are populated from the
+// error catalog (see RegisterError).
+func (r *Responder) newErrorPayload(errType error, errMsg string, w http.ResponseWriter) ErrorPayload {
+	info := r.errorInfoFor(errType)
+	if errMsg == "" {
+		errMsg = info.Message
+	}
+
+	ep := ErrorPayload{
+		Code:      info.Code,
+		Error:     errType.Error(),
+		Message:   errMsg,
+		DocsURL:   info.DocsURL,
+		Retryable: info.Retryable,
+	}
+
+	debug := r.debugging(w)
+	if debug || r.verboseErrors {
+		ep.Chain = errorChain(errType)
+	}
+
+	if debug && r.stackTraces {
+		ep.Stack = stackTrace()
+	}
+
+	return ep
+}
+
+// sendError builds an error Payload and sends it with the provided message type and
+// response code. data is typically nil, but can be used to send an ID (see
+// ErrorWithID).
+func (r *Responder) sendError(msgType MessageType, errType error, errMsg string, data interface{}, w http.ResponseWriter, responseCode int, opts ...SendOption) (err error) {
+	//Define the error related data.
+	ep := r.newErrorPayload(errType, errMsg, w)
+
+	//Logging of errors can be used for diagnostics.
+	r.log(w, "output.Error", "type", msgType, "status", responseCode, "error", errType, "message", errMsg, "data", data)
+
+	err = r.buildAndSend(false, msgType, data, ep, w, responseCode, opts...)
+	return
+}
+
+// ErrorInputInvalid sends an input validation error. See the package-level
+// ErrorInputInvalid.
+func (r *Responder) ErrorInputInvalid(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.Error(errInputInvalid, msg, w, opts...)
+	return
+}
+
+// ErrorAlreadyExists sends an already exists error. See the package-level
+// ErrorAlreadyExists.
+func (r *Responder) ErrorAlreadyExists(msg string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.Error(errAlreadyExists, msg, w, opts...)
+	return
+}
+
+// ErrorWithID sends an error along with an ID. See the package-level ErrorWithID.
+func (r *Responder) ErrorWithID(errType error, errMsg string, id int64, w http.ResponseWriter, opts ...SendOption) (err error) {
+	code, msgType := r.mapError(errType)
+	err = r.sendError(msgType, errType, errMsg, id, w, code, opts...)
+	return
+}
+
+// ErrorInputInvalidWithID sends an input validation error along with an ID. See
+// the package-level ErrorInputInvalidWithID.
+func (r *Responder) ErrorInputInvalidWithID(msg string, id int64, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.ErrorWithID(errInputInvalid, msg, id, w, opts...)
+	return
+}
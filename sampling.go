@@ -0,0 +1,87 @@
+package output
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Sample is a summary of a response, see WithSampling.
+type Sample struct {
+	//Type is the Payload's Type and OK is the Payload's OK.
+	Type string
+	OK   bool
+
+	//ErrorCode is the ErrorPayload's Code for error responses.
+	ErrorCode string
+
+	//ResponseCode is the HTTP status code that was sent.
+	ResponseCode int
+
+	//Size is the number of bytes of the response body that were written.
+	Size int
+
+	//Duration is how long it took to encode and write the response.
+	Duration time.Duration
+
+	//Data is the Payload's Data, with sensitive fields redacted, see
+	//WithRedactedFields.
+	Data interface{}
+
+	//Method and Path are the HTTP method and URL path of the request that was
+	//responded to. These are blank unless the ResponseWriter carried the request,
+	//see Negotiate.
+	Method string
+	Path   string
+
+	//RequestID is the Payload's RequestID.
+	RequestID string
+
+	//Time is when the response was sent.
+	Time time.Time
+}
+
+// SampleSink receives summaries of a sample of responses, for example to build usage
+// analytics without logging every response. Implement SampleSink for the analytics
+// system you use.
+type SampleSink interface {
+	//ObserveSample is called after a sampled response is sent. This is called
+	//synchronously, so slow sinks should hand off the Sample, for example to a
+	//buffered channel.
+	ObserveSample(s Sample)
+}
+
+// WithSampling sends a summary of a percentage, from 0 to 100, of responses to s.
+//
+//	r := output.New(output.WithSampling(1, sink))
+func WithSampling(percent float64, s SampleSink) Option {
+	return func(r *Responder) {
+		r.samplePercent = percent
+		r.sampleSink = s
+	}
+}
+
+// sample sends a summary of the response to the SampleSink if the response is
+// chosen at random.
+func (r *Responder) sample(res SendResult) {
+	if r.sampleSink == nil || rand.Float64()*100 >= r.samplePercent {
+		return
+	}
+
+	s := Sample{
+		Type:         res.Payload.Type,
+		OK:           res.Payload.OK,
+		ErrorCode:    res.Payload.ErrorData.Code,
+		ResponseCode: res.ResponseCode,
+		Size:         res.Size,
+		Duration:     res.Duration,
+		Data:         res.Payload.Data,
+		RequestID:    res.Payload.RequestID,
+		Time:         time.Now().UTC(),
+	}
+	if res.Request != nil {
+		s.Method = res.Request.Method
+		s.Path = res.Request.URL.Path
+	}
+
+	r.sampleSink.ObserveSample(s)
+}
@@ -0,0 +1,257 @@
+package output
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonSchemaDialect is the JSON Schema version used by Schema.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema returns a JSON Schema describing the Payload sent by the default
+// Responder. See Responder.Schema.
+func Schema() []byte {
+	return std.Schema()
+}
+
+// Schema returns a JSON Schema describing the Payload, and the ErrorPayload and
+// other types defined in this package that are part of it, as sent by the
+// Responder. Keys match the Responder's configuration, see WithKeyCase,
+// WithFieldNames, WithDatetimeField, and WithOmitDatetime, so that clients can
+// generate validators and types from the authoritative source.
+//
+// Data, Meta, and ErrorData's Details are user-defined so any value is allowed.
+// The schema describes the Payload, not JSON:API or HAL documents or problem
+// details, see WithJSONAPI, WithHAL, and WithProblemDetails.
+func (r *Responder) Schema() []byte {
+	sb := newSchemaBuilder(r.keys, "#/$defs/")
+
+	s := r.envelopeSchema(sb)
+	s["$schema"] = jsonSchemaDialect
+	s["title"] = "Payload"
+	s["$defs"] = sb.defs
+
+	//This cannot fail since the schema only contains maps, slices, and strings.
+	b, _ := json.Marshal(s)
+	return b
+}
+
+// envelopeSchema returns the JSON Schema of the Payload. The schemas of the types
+// defined in this package that are part of the Payload are added to sb's defs.
+func (r *Responder) envelopeSchema(sb *schemaBuilder) map[string]interface{} {
+	s := sb.structSchema(reflect.TypeOf(Payload{}), true)
+
+	if r.omitDatetime {
+		key := sb.keys.key("Datetime", "Datetime")
+		delete(s["properties"].(map[string]interface{}), key)
+		s["required"] = removeString(s["required"].([]string), key)
+	}
+
+	return s
+}
+
+// schemaBuilder builds JSON Schemas from Go types using reflection.
+type schemaBuilder struct {
+	//keys alters the keys of the Payload and types defined in this package.
+	keys *envelopeKeys
+
+	//refPrefix is prepended to the names of defs when referenced with $ref.
+	refPrefix string
+
+	//defs are the schemas of named struct types, referenced with $ref, and
+	//defTypes are the types of each def, keyed by name.
+	defs     map[string]interface{}
+	defTypes map[string]reflect.Type
+}
+
+// newSchemaBuilder returns a schemaBuilder that references defs using refPrefix.
+func newSchemaBuilder(keys *envelopeKeys, refPrefix string) *schemaBuilder {
+	return &schemaBuilder{
+		keys:      keys,
+		refPrefix: refPrefix,
+		defs:      map[string]interface{}{},
+		defTypes:  map[string]reflect.Type{},
+	}
+}
+
+// typeSchema returns the JSON Schema for values of t. If envelope is true, t is
+// part of the Payload and its keys are altered per the Responder's configuration,
+// otherwise t is user-defined, such as the type of Data, and keys are used as is.
+func (sb *schemaBuilder) typeSchema(t reflect.Type, envelope bool) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case implements(t, jsonMarshalerType):
+		return map[string]interface{}{}
+	case implements(t, textMarshalerType):
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+
+	case reflect.Slice, reflect.Array:
+		//Byte slices are encoded as base64 strings.
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+
+		return map[string]interface{}{
+			"type":  "array",
+			"items": sb.typeSchema(t.Elem(), envelope),
+		}
+
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": sb.typeSchema(t.Elem(), envelope),
+		}
+
+	case reflect.Struct:
+		//Named structs are defined once and referenced so that types used more than
+		//once, such as ErrorPayload, are not repeated.
+		if t.Name() == "" {
+			return sb.structSchema(t, envelope)
+		}
+
+		name, found := sb.defName(t)
+		if !found {
+			//Set the type first so that recursive types terminate.
+			sb.defTypes[name] = t
+			sb.defs[name] = sb.structSchema(t, envelope)
+		}
+
+		return map[string]interface{}{"$ref": sb.refPrefix + name}
+
+	default:
+		//Interfaces, such as Data, can be anything.
+		return map[string]interface{}{}
+	}
+}
+
+// defName returns the name of the def for the named type t, and if the def already
+// exists. Types from different packages with the same name, such as a user-defined
+// Links type, are given a numeric suffix.
+func (sb *schemaBuilder) defName(t reflect.Type) (name string, found bool) {
+	name = t.Name()
+	for i := 2; ; i++ {
+		dt, ok := sb.defTypes[name]
+		if !ok {
+			return name, false
+		}
+		if dt == t {
+			return name, true
+		}
+
+		name = t.Name() + strconv.Itoa(i)
+	}
+}
+
+// structSchema returns the JSON Schema for the struct type t. Fields are handled as
+// encoding/json does, using each field's json tag.
+func (sb *schemaBuilder) structSchema(t reflect.Type, envelope bool) map[string]interface{} {
+	props := map[string]interface{}{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, skip := parseJSONTag(f)
+		if skip {
+			continue
+		}
+
+		key := name
+		if envelope {
+			if t == reflect.TypeOf(Payload{}) {
+				key = sb.keys.key(name, name)
+			} else if sb.keys != nil {
+				key = convertCase(name, sb.keys.keyCase)
+			}
+		}
+
+		//The values of user-defined fields, such as Meta, are not altered.
+		props[key] = sb.typeSchema(f.Type, envelope && !userKeys[name])
+
+		//omitempty has no effect on structs so they are always encoded.
+		if !omitEmpty || f.Type.Kind() == reflect.Struct {
+			required = append(required, key)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+		"required":   required,
+	}
+}
+
+// Types that are handled specially since they encode themselves. The schema of
+// types implementing json.Marshaler is unknown so any value is allowed, types
+// implementing encoding.TextMarshaler are encoded as strings.
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// implements reports if t, or a pointer to t, implements iface.
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// parseJSONTag returns the key a struct field is encoded with, if the field is
+// omitted when empty, and if the field is never encoded.
+func parseJSONTag(f reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+
+	for _, o := range strings.Split(opts, ",") {
+		if o == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return
+}
+
+// removeString returns a without s.
+func removeString(a []string, s string) []string {
+	out := a[:0]
+	for _, v := range a {
+		if v != s {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,252 @@
+package output
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SendOption is used to alter a single response, versus an Option which configures
+// every response sent by a Responder. SendOptions can be provided to Success, Error,
+// and all related funcs. This allows for one-off deviations, such as returning a 201
+// from InsertOK, without having to build a Payload and use Send.
+//
+//	output.InsertOK(id, w, output.WithStatusCode(http.StatusCreated))
+type SendOption func(*sendOptions)
+
+// sendOptions is the result of applying SendOptions.
+type sendOptions struct {
+	//responseCode overrides the HTTP status code if set.
+	responseCode int
+
+	//msgType overrides the Payload's Type if set.
+	msgType MessageType
+
+	//meta is merged into the Payload's Meta.
+	meta map[string]interface{}
+
+	//warnings are appended to the Payload's Warnings.
+	warnings []Warning
+
+	//header is added to the response's headers.
+	header http.Header
+
+	//linkHeader are added to the response's Link header, after any existing links.
+	linkHeader []string
+
+	//links is set as the Payload's Links if set.
+	links *Links
+
+	//retryAfter is the number of seconds a client should wait before retrying,
+	//or checking the status of, a request.
+	retryAfter int
+
+	//requestID is set as the Payload's RequestID if set.
+	requestID string
+
+	//ctx is the context of the request being responded to, see SendCtx. This is
+	//used instead of the context of the request carried by the ResponseWriter.
+	ctx context.Context
+
+	//raw causes only the Payload's Data to be sent. See WithRaw.
+	raw bool
+
+	//retryable overrides the ErrorPayload's Retryable if set.
+	retryable *bool
+
+	//stream is read to get the Payload's Data. See DataFoundReader.
+	stream *dataStream
+
+	//cookies are set on the response.
+	cookies []*http.Cookie
+
+	//etag is set as the response's ETag if set.
+	etag string
+
+	//cache is sent as the response's Cache-Control header if set.
+	cache *CachePolicy
+
+	//filename is the name of the file the response is downloaded as if set.
+	filename string
+
+	//skipSchema skips validating Data against its schema. See RegisterSchema.
+	skipSchema bool
+
+	//truncateBody causes only part of the body to be sent. See FaultTruncate.
+	truncateBody bool
+}
+
+// WithStatusCode sets the HTTP status code of the response, overriding the status
+// code the func would otherwise use. Codes less than 100 are ignored.
+func WithStatusCode(code int) SendOption {
+	return func(o *sendOptions) {
+		if code >= http.StatusContinue {
+			o.responseCode = code
+		}
+	}
+}
+
+// WithHeader sets a header on the response. Headers set this way override headers
+// set by this package, such as Content-Type.
+//
+// Headers provided with SendOptions are set just before the response code is
+// written, so they are always sent, but only if the response is actually sent. For
+// example, they are not set if maintenance mode replaces the response.
+func WithHeader(key, value string) SendOption {
+	return func(o *sendOptions) {
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+
+		o.header.Set(key, value)
+	}
+}
+
+// WithHeaders sets each header in h on the response. See WithHeader.
+func WithHeaders(h http.Header) SendOption {
+	return func(o *sendOptions) {
+		if o.header == nil {
+			o.header = make(http.Header, len(h))
+		}
+
+		for k, v := range h {
+			o.header[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+		}
+	}
+}
+
+// WithType sets the Payload's Type, overriding the message type the func would
+// otherwise use.
+func WithType(msgType MessageType) SendOption {
+	return func(o *sendOptions) {
+		o.msgType = msgType
+	}
+}
+
+// WithMeta adds m to the Payload's Meta. Keys in m replace existing keys.
+func WithMeta(m map[string]interface{}) SendOption {
+	return func(o *sendOptions) {
+		if o.meta == nil {
+			o.meta = make(map[string]interface{}, len(m))
+		}
+
+		for k, v := range m {
+			o.meta[k] = v
+		}
+	}
+}
+
+// WithRetryAfter sets the Retry-After header, to a number of seconds, telling the
+// client how long to wait before retrying the request or checking the status of a
+// job. Durations are rounded up to the nearest second.
+//
+// When used with an error response, the ErrorPayload's RetryAfterSeconds is set and
+// the error is marked as Retryable unless WithRetryable(false) is also provided.
+func WithRetryAfter(d time.Duration) SendOption {
+	return func(o *sendOptions) {
+		o.retryAfter = int((d + time.Second - 1) / time.Second)
+		if o.retryAfter < 0 {
+			o.retryAfter = 0
+		}
+
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+
+		o.header.Set("Retry-After", strconv.Itoa(o.retryAfter))
+	}
+}
+
+// WithRetryable sets the ErrorPayload's Retryable, overriding the value from the
+// error catalog (see RegisterError). This has no effect on successful responses.
+//
+//	output.Error(err, "Could not reach the payment processor.", w, output.WithRetryable(true))
+func WithRetryable(retryable bool) SendOption {
+	return func(o *sendOptions) {
+		o.retryable = &retryable
+	}
+}
+
+// withContext sets the context of the request being responded to.
+func withContext(ctx context.Context) SendOption {
+	return func(o *sendOptions) {
+		o.ctx = ctx
+	}
+}
+
+// applySendOptions returns the result of applying each SendOption.
+func applySendOptions(opts []SendOption) (o sendOptions) {
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return
+}
+
+// apply alters the Payload and response code being sent based on the options.
+func (o sendOptions) apply(p *Payload, responseCode int) int {
+	if o.msgType != "" {
+		p.Type = string(o.msgType)
+	}
+
+	//A new map is used so that a map provided by the caller, such as with Send,
+	//is not modified.
+	if len(o.meta) > 0 {
+		meta := make(map[string]interface{}, len(p.Meta)+len(o.meta))
+		for k, v := range p.Meta {
+			meta[k] = v
+		}
+		for k, v := range o.meta {
+			meta[k] = v
+		}
+
+		p.Meta = meta
+	}
+
+	//A new slice is used so that a slice provided by the caller, such as with
+	//Send, is not modified.
+	if len(o.warnings) > 0 {
+		warnings := make([]Warning, 0, len(p.Warnings)+len(o.warnings))
+		warnings = append(warnings, p.Warnings...)
+		p.Warnings = append(warnings, o.warnings...)
+	}
+
+	if o.links != nil {
+		p.Links = o.links
+	}
+
+	if o.requestID != "" {
+		p.RequestID = o.requestID
+	}
+
+	if !p.OK {
+		if o.retryAfter > 0 {
+			p.ErrorData.RetryAfterSeconds = o.retryAfter
+			p.ErrorData.Retryable = true
+		}
+		if o.retryable != nil {
+			p.ErrorData.Retryable = *o.retryable
+		}
+	}
+
+	if o.responseCode != 0 {
+		responseCode = o.responseCode
+	}
+
+	return responseCode
+}
+
+// setHeaders adds the headers provided with WithHeader, and other SendOptions, to
+// the response.
+func (o sendOptions) setHeaders(w http.ResponseWriter) {
+	if o.filename != "" {
+		w.Header().Set("Content-Disposition", contentDisposition(o.filename))
+	}
+
+	for k, v := range o.header {
+		w.Header()[k] = v
+	}
+
+	o.addLinks(w)
+}
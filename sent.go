@@ -0,0 +1,28 @@
+package output
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrAlreadySent is returned when a response is sent to a ResponseWriter that has
+// already been used to send a response, for example when a handler calls Error and
+// then continues on to call DataFound because it did not return. Nothing is sent,
+// instead of net/http's confusing "superfluous WriteHeader call" log.
+//
+// This is only detected when the ResponseWriter carries the request, see Negotiate.
+var ErrAlreadySent = errors.New("output: response already sent")
+
+// alreadySent reports if a response has already been written to w. Each
+// requestWriter that w wraps is checked, since w may have been passed to Negotiate
+// more than once, for example by middleware, and the response may have been written
+// through any of them.
+func alreadySent(w http.ResponseWriter) bool {
+	for rw := unwrapRequestWriter(w); rw != nil; rw = unwrapRequestWriter(rw.ResponseWriter) {
+		if rw.wroteHeader {
+			return true
+		}
+	}
+
+	return false
+}
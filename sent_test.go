@@ -0,0 +1,63 @@
+package output
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAlreadySent(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(w http.ResponseWriter, req *http.Request) (first, second http.ResponseWriter)
+	}{
+		{
+			name: "same writer",
+			wrap: func(w http.ResponseWriter, req *http.Request) (http.ResponseWriter, http.ResponseWriter) {
+				w = Negotiate(w, req)
+				return w, w
+			},
+		},
+		{
+			name: "negotiated twice, sent through outer then inner",
+			wrap: func(w http.ResponseWriter, req *http.Request) (http.ResponseWriter, http.ResponseWriter) {
+				inner := Negotiate(w, req)
+				return Negotiate(inner, req), inner
+			},
+		},
+		{
+			name: "negotiated twice, sent through inner then outer",
+			wrap: func(w http.ResponseWriter, req *http.Request) (http.ResponseWriter, http.ResponseWriter) {
+				inner := Negotiate(w, req)
+				return inner, Negotiate(inner, req)
+			},
+		},
+		{
+			name: "negotiated twice, sent through outer twice",
+			wrap: func(w http.ResponseWriter, req *http.Request) (http.ResponseWriter, http.ResponseWriter) {
+				outer := Negotiate(Negotiate(w, req), req)
+				return outer, outer
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			first, second := tt.wrap(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if err := DataFound("a", first); err != nil {
+				t.Fatal(err)
+			}
+			body := rec.Body.String()
+
+			if err := DataFound("b", second); !errors.Is(err, ErrAlreadySent) {
+				t.Fatalf("got error %v, want ErrAlreadySent", err)
+			}
+			if rec.Body.String() != body {
+				t.Fatalf("got body %q, want %q", rec.Body.String(), body)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package output
+
+// WithSequence causes a sequence number to be included in each Payload sent by the
+// Responder. The sequence number starts at 1 and increases by one with each
+// response, so polling and streaming clients can detect missed or out-of-order
+// responses. See Payload's Sequence.
+//
+// The sequence is per Responder, not per client, so clients should expect gaps
+// when the Responder is shared by multiple clients or endpoints; decreasing numbers
+// indicate responses arrived out of order. The sequence restarts when the process
+// restarts.
+func WithSequence(b bool) Option {
+	return func(r *Responder) {
+		r.sequence = b
+	}
+}
+
+// nextSequence returns the next sequence number, or 0 if sequence numbers are not
+// enabled.
+func (r *Responder) nextSequence() uint64 {
+	if !r.sequence {
+		return 0
+	}
+
+	return r.seq.Add(1)
+}
@@ -0,0 +1,35 @@
+package output
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+// HeaderSignature is the header used to send the signature of the response body,
+// see WithSigningKey.
+const HeaderSignature = "X-Signature-SHA256"
+
+// WithSigningKey causes the body of every response to be signed with HMAC-SHA256
+// using key. The hex encoded signature is sent in the X-Signature-SHA256 header so
+// that clients holding the same key can verify the response was not altered by an
+// intermediary. The signature is calculated over the exact bytes sent, after any
+// pretty-printing or canonicalization.
+func WithSigningKey(key []byte) Option {
+	return func(r *Responder) {
+		r.signingKey = append([]byte(nil), key...)
+	}
+}
+
+// sign sets the signature of body in the response headers h, if a signing key was
+// provided.
+func (r *Responder) sign(h http.Header, body []byte) {
+	if len(r.signingKey) == 0 {
+		return
+	}
+
+	mac := hmac.New(sha256.New, r.signingKey)
+	mac.Write(body)
+	h.Set(HeaderSignature, hex.EncodeToString(mac.Sum(nil)))
+}
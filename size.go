@@ -0,0 +1,109 @@
+package output
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// ErrResponseTooLarge is returned when an encoded response is larger than the limit
+// set with WithMaxResponseSize. The response is not sent.
+var ErrResponseTooLarge = errors.New("output: response too large")
+
+// errResponseTooLarge is the error sent in place of a response that is too large.
+var errResponseTooLarge = errors.New("response too large")
+
+// defaultResponseTooLargeMsg is the Message sent in place of a response that is too
+// large.
+const defaultResponseTooLargeMsg = "The response was too large to send, please request less data."
+
+// WithMaxResponseSize sets the maximum size, in bytes, of an encoded response. A
+// response larger than n is not sent and ErrResponseTooLarge is returned instead.
+// This prevents huge responses, for example from a query that was not paginated,
+// from being slowly sent to clients that will time out before receiving them.
+//
+// If sendError is true an HTTP status 500 error Payload with the
+// TypeResponseTooLarge message type is sent in place of the response, otherwise
+// nothing is sent and you must handle the error yourself.
+func WithMaxResponseSize(n int, sendError bool) Option {
+	return func(r *Responder) {
+		r.maxResponseSize = n
+		r.sendResponseTooLarge = sendError
+	}
+}
+
+// tooLargeHeaders are the headers, describing the response that was too large, that
+// must not be sent with the error sent in its place.
+var tooLargeHeaders = []string{
+	"ETag",
+	"Last-Modified",
+	"Cache-Control",
+	"Set-Cookie",
+	"Content-Disposition",
+	"Content-Encoding",
+	"Content-Length",
+	"Content-Range",
+	"Accept-Ranges",
+	HeaderOutputOK,
+	HeaderOutputType,
+	HeaderOutputDatetime,
+	HeaderOutputRequestID,
+	HeaderOutputSequence,
+}
+
+// tooLarge reports if the encoded response body is larger than the maximum response
+// size.
+func (r *Responder) tooLarge(w http.ResponseWriter, body []byte) bool {
+	if r.maxResponseSize <= 0 || len(body) <= r.maxResponseSize {
+		return false
+	}
+
+	r.log(w, "output.send: response too large", "size", len(body), "max", r.maxResponseSize)
+	return true
+}
+
+// sendTooLarge replaces p with an error Payload, with the TypeResponseTooLarge
+// message type, and writes it to w, encoded with enc, with an HTTP status 500. This
+// is done within the send of the response that was too large, versus sending a new
+// response, so that hooks and metrics see a single response. The error is not
+// checked against the maximum response size since it is always small.
+func (r *Responder) sendTooLarge(p *Payload, w http.ResponseWriter, enc Encoder) (size int) {
+	ep := r.newErrorPayload(errResponseTooLarge, defaultResponseTooLargeMsg, w)
+	fallback := r.newPayload(false, TypeResponseTooLarge, nil, ep)
+	fallback.RequestID = p.RequestID
+	fallback.TraceID = p.TraceID
+	fallback.SpanID = p.SpanID
+	fallback.DurationMS = p.DurationMS
+	fallback.APIVersion = p.APIVersion
+	fallback.Sequence = p.Sequence
+	r.setEnvelopeKeys(&fallback)
+	*p = fallback
+
+	r.log(w, "output.Error", "type", TypeResponseTooLarge, "status", http.StatusInternalServerError, "error", errResponseTooLarge, "message", defaultResponseTooLargeMsg)
+
+	//Remove the headers set for the response that was too large.
+	h := w.Header()
+	for _, k := range tooLargeHeaders {
+		h.Del(k)
+	}
+	h.Set("Cache-Control", "no-store")
+
+	b := getBuffer()
+	defer putBuffer(b)
+
+	err := enc.Encode(b, p)
+	if err != nil {
+		r.marshalFailed(w, p, err)
+		return
+	}
+
+	h.Set("Content-Type", enc.ContentType())
+	h.Set("Content-Length", strconv.Itoa(b.Len()))
+	w.WriteHeader(http.StatusInternalServerError)
+	if isHead(w) {
+		return
+	}
+
+	size, _ = w.Write(b.Bytes())
+	return
+}
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxResponseSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		max       int
+		sendError bool
+		data      interface{}
+		opts      []SendOption
+		wantErr   error
+		wantCode  int
+		wantType  MessageType
+		wantEmpty bool
+	}{
+		{
+			name:     "under limit",
+			max:      1024,
+			data:     "small",
+			wantCode: http.StatusOK,
+			wantType: TypeDataFound,
+		},
+		{
+			name:      "over limit without error",
+			max:       50,
+			data:      strings.Repeat("a", 100),
+			wantErr:   ErrResponseTooLarge,
+			wantCode:  http.StatusOK,
+			wantEmpty: true,
+		},
+		{
+			name:      "over limit with error",
+			max:       50,
+			sendError: true,
+			data:      strings.Repeat("a", 100),
+			wantErr:   ErrResponseTooLarge,
+			wantCode:  http.StatusInternalServerError,
+			wantType:  TypeResponseTooLarge,
+		},
+		{
+			name:      "over limit with per-response headers",
+			max:       50,
+			sendError: true,
+			data:      strings.Repeat("a", 100),
+			opts: []SendOption{
+				WithETag("v1"),
+				WithCookie(&http.Cookie{Name: "session", Value: "abc"}),
+			},
+			wantErr:  ErrResponseTooLarge,
+			wantCode: http.StatusInternalServerError,
+			wantType: TypeResponseTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				calls  int
+				result SendResult
+			)
+			r := New(
+				WithMaxResponseSize(tt.max, tt.sendError),
+				WithAfterSend(func(res SendResult) {
+					calls++
+					result = res
+				}),
+			)
+
+			w := httptest.NewRecorder()
+			err := r.DataFound(tt.data, w, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if w.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d", w.Code, tt.wantCode)
+			}
+			if calls != 1 {
+				t.Fatalf("after send hooks called %d times, want 1", calls)
+			}
+			if result.ResponseCode != tt.wantCode {
+				t.Fatalf("hook got status %d, want %d", result.ResponseCode, tt.wantCode)
+			}
+
+			if tt.wantEmpty {
+				if w.Body.Len() != 0 {
+					t.Fatalf("got body %q, want empty", w.Body.String())
+				}
+				return
+			}
+
+			var p Payload
+			if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+				t.Fatalf("could not decode body %q: %v", w.Body.String(), err)
+			}
+			if p.Type != string(tt.wantType) {
+				t.Fatalf("got type %q, want %q", p.Type, tt.wantType)
+			}
+			if result.Payload.Type != string(tt.wantType) {
+				t.Fatalf("hook got type %q, want %q", result.Payload.Type, tt.wantType)
+			}
+
+			if tt.wantCode != http.StatusInternalServerError {
+				return
+			}
+			if p.OK || p.ErrorData.Code != "responseTooLarge" {
+				t.Fatalf("got error payload %+v", p)
+			}
+			for _, k := range []string{"ETag", "Set-Cookie", "Content-Disposition"} {
+				if v := w.Header().Get(k); v != "" {
+					t.Fatalf("got %s header %q, want none", k, v)
+				}
+			}
+			if v := w.Header().Get("Cache-Control"); v != "no-store" {
+				t.Fatalf("got Cache-Control %q, want no-store", v)
+			}
+		})
+	}
+}
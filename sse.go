@@ -0,0 +1,154 @@
+package output
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventStream sends Payloads as server-sent events. This allows long-lived endpoints
+// to use the same Payload format that clients already parse. Each event's name is
+// set to the Payload's Type and the event's data is the JSON encoded Payload.
+//
+// An EventStream is safe for concurrent use, such as sending events while
+// KeepAlive is running.
+type EventStream struct {
+	r  *Responder
+	w  http.ResponseWriter
+	rc *http.ResponseController
+	mu sync.Mutex
+}
+
+// NewEventStream starts a server-sent event stream on w using the default
+// Responder. See Responder.NewEventStream.
+func NewEventStream(w http.ResponseWriter) (s *EventStream, err error) {
+	s, err = std.NewEventStream(w)
+	return
+}
+
+// NewEventStream starts a server-sent event stream on w. The response headers are
+// sent immediately. An error is returned if w does not support flushing since
+// events would otherwise be buffered and not sent to the client.
+func (r *Responder) NewEventStream(w http.ResponseWriter) (s *EventStream, err error) {
+	s = &EventStream{
+		r:  r,
+		w:  w,
+		rc: http.NewResponseController(w),
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream; charset=UTF-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	err = s.rc.Flush()
+	if err != nil {
+		r.log(w, "output.NewEventStream: could not flush", "error", err)
+		return
+	}
+
+	return
+}
+
+// Send sends a Payload as an event. The Payload is prepared the same as with Send,
+// for example the Datetime and RequestID are set if they were not provided, and the
+// Responder's key case and default Meta are used.
+func (s *EventStream) Send(p Payload) (err error) {
+	//The request's context, if w carries the request, provides the request ID.
+	var ctx context.Context
+	if req := requestFrom(s.w); req != nil {
+		ctx = req.Context()
+	}
+
+	j, err := s.r.encodeMessage(ctx, &p)
+	if err != nil {
+		return
+	}
+
+	//Event names cannot contain line breaks.
+	event := strings.NewReplacer("\r", "", "\n", "").Replace(p.Type)
+
+	//The sequence number is used as the event's ID so that clients can provide it
+	//in the Last-Event-ID header when reconnecting.
+	var id string
+	if p.Sequence > 0 {
+		id = "id: " + strconv.FormatUint(p.Sequence, 10) + "\n"
+	}
+
+	err = s.write(id + "event: " + event + "\ndata: " + string(j) + "\n\n")
+	return
+}
+
+// Success sends a successful Payload as an event.
+func (s *EventStream) Success(msgType MessageType, data interface{}) (err error) {
+	err = s.Send(Payload{
+		OK:   true,
+		Type: string(msgType),
+		Data: data,
+	})
+	return
+}
+
+// Error sends an error Payload as an event.
+func (s *EventStream) Error(errType error, errMsg string) (err error) {
+	s.r.log(s.w, "output.EventStream.Error", "error", errType, "message", errMsg)
+
+	err = s.Send(Payload{
+		OK:        false,
+		Type:      string(TypeError),
+		ErrorData: s.r.newErrorPayload(errType, errMsg, s.w),
+	})
+	return
+}
+
+// Comment sends a comment line. Comments are ignored by clients but keep the
+// connection from being closed by proxies due to inactivity.
+func (s *EventStream) Comment(text string) (err error) {
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(": " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	err = s.write(b.String())
+	return
+}
+
+// KeepAlive sends a comment every interval until ctx is done or a comment cannot be
+// sent. This is typically run in a goroutine with the request's context.
+//
+//	go stream.KeepAlive(r.Context(), 15*time.Second)
+func (s *EventStream) KeepAlive(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := s.Comment("keep-alive"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// write writes to the stream and flushes so the client receives the data
+// immediately.
+func (s *EventStream) write(str string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = io.WriteString(s.w, str)
+	if err != nil {
+		return
+	}
+
+	err = s.rc.Flush()
+	return
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEventStreamSend(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		requestID string
+		p         Payload
+		want      []string
+	}{
+		{
+			name: "default",
+			p:    Payload{OK: true, Type: "priceChanged", Data: 1},
+			want: []string{"event: priceChanged\n", `"OK":true`, `"Data":1`},
+		},
+		{
+			name: "key case",
+			opts: []Option{WithKeyCase(KeyCaseSnake)},
+			p:    Payload{OK: true, Type: "priceChanged", APIVersion: "v1"},
+			want: []string{`"ok":true`, `"api_version":"v1"`},
+		},
+		{
+			name: "default meta",
+			opts: []Option{WithDefaultMeta(map[string]interface{}{"region": "us"})},
+			p:    Payload{OK: true, Type: "priceChanged"},
+			want: []string{`"Meta":{"region":"us"}`},
+		},
+		{
+			name:      "request id",
+			requestID: "abc123",
+			p:         Payload{OK: true, Type: "priceChanged"},
+			want:      []string{`"RequestID":"abc123"`},
+		},
+		{
+			name: "event name line breaks",
+			p:    Payload{OK: true, Type: "price\nChanged"},
+			want: []string{"event: priceChanged\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.opts...)
+
+			req := httptest.NewRequest("GET", "/events", nil)
+			if tt.requestID != "" {
+				req = req.WithContext(context.WithValue(req.Context(), requestIDKey{}, tt.requestID))
+			}
+			rec := httptest.NewRecorder()
+
+			s, err := r.NewEventStream(Negotiate(rec, req))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Send(tt.p); err != nil {
+				t.Fatal(err)
+			}
+
+			body := rec.Body.String()
+			for _, w := range tt.want {
+				if !strings.Contains(body, w) {
+					t.Fatalf("body %q does not contain %q", body, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package output
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// maxStackDepth is the maximum number of frames included in an ErrorPayload's
+// Stack.
+const maxStackDepth = 32
+
+// WithStackTraces causes a trimmed stack trace, showing where the error response
+// was sent from, to be included in error responses when debug is enabled. See
+// ErrorPayload's Stack.
+//
+// This is meant for local development and staging environments so that you can see
+// where an error response originated without searching through logs.
+func WithStackTraces(b bool) Option {
+	return func(r *Responder) {
+		r.stackTraces = b
+	}
+}
+
+// stackTrace returns the stack of the goroutine calling into this package. Frames
+// within this package and the Go runtime are trimmed.
+func stackTrace() (stack []string) {
+	pcs := make([]uintptr, maxStackDepth+16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		f, more := frames.Next()
+
+		inPkg := strings.HasPrefix(f.Function, pkgPath+".")
+		inRuntime := strings.HasPrefix(f.Function, "runtime.")
+		if !inPkg && !inRuntime {
+			stack = append(stack, fmt.Sprintf("%s (%s:%d)", f.Function, f.File, f.Line))
+		}
+
+		if !more || len(stack) == maxStackDepth {
+			break
+		}
+	}
+
+	return
+}
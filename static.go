@@ -0,0 +1,100 @@
+package output
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+)
+
+// StaticResponse is a response that is encoded once, when it is created, and written
+// as is each time it is sent. This is used for hot endpoints that always send the
+// same response, such as a health check or a fixed error, to avoid encoding the
+// same Payload on every request.
+//
+// A StaticResponse is an http.Handler so it can be used directly as an endpoint.
+type StaticResponse struct {
+	code   int
+	header http.Header
+	body   []byte
+}
+
+// NewStaticResponse encodes p, using the default Responder, for sending many times.
+// See Responder.NewStaticResponse.
+func NewStaticResponse(p Payload, responseCode int) (s *StaticResponse, err error) {
+	s, err = std.NewStaticResponse(p, responseCode)
+	return
+}
+
+// NewStaticResponse encodes p for sending many times with responseCode.
+//
+// The Payload is encoded as JSON, with the Responder's default headers and Meta,
+// key casing, and JSON formatting options, at the time NewStaticResponse is called.
+// Since the response never changes, Datetime is omitted and nothing that differs
+// per request, such as the request ID, negotiated encoding, hooks, or metrics, is
+// used.
+//
+//	health, err := output.NewStaticResponse(output.Payload{OK: true, Type: "healthy"}, http.StatusOK)
+//	http.Handle("/health", health)
+func (r *Responder) NewStaticResponse(p Payload, responseCode int) (s *StaticResponse, err error) {
+	if responseCode < http.StatusContinue {
+		err = ErrInvalidResponseCode
+		return
+	}
+
+	if !p.ErrorData.IsZero() || len(p.Errors) > 0 {
+		p.OK = false
+	}
+	p.Datetime = ""
+
+	s = &StaticResponse{
+		code:   responseCode,
+		header: http.Header{},
+	}
+
+	r.setEnvelopeKeys(&p)
+	r.setDefaultHeaders(&p, s.header)
+	r.setDefaultMeta(&p)
+
+	var b bytes.Buffer
+	err = JSON.Encode(&b, p)
+	if err != nil {
+		s = nil
+		return
+	}
+
+	body := b.Bytes()
+	if r.canonicalJSON {
+		body = canonicalJSON(body)
+	}
+	if !r.escapeHTML {
+		body = unescapeHTML(body)
+	}
+	if r.prettyJSON && !r.canonicalJSON {
+		body = indentJSON(body)
+	}
+	s.body = body
+
+	s.header.Set("Content-Type", JSON.ContentType())
+	s.header.Set("Content-Length", strconv.Itoa(len(body)))
+	r.sign(s.header, body)
+
+	return
+}
+
+// Send writes the response to w.
+func (s *StaticResponse) Send(w http.ResponseWriter) (err error) {
+	//The values are copied so that the static headers cannot be modified through
+	//w.
+	for k, v := range s.header {
+		w.Header()[k] = append([]string(nil), v...)
+	}
+	w.WriteHeader(s.code)
+
+	_, err = w.Write(s.body)
+	return
+}
+
+// ServeHTTP implements http.Handler.
+func (s *StaticResponse) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.Send(w)
+}
@@ -0,0 +1,269 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidJSON is returned by DataFoundReader when content with a JSON content
+// hint is read in full, rather than streamed, and is not valid JSON.
+var ErrInvalidJSON = errors.New("output: content is not valid JSON")
+
+// streamPlaceholder is set as Data, and replaced by the streamed content, when Data
+// is streamed from a reader. The NUL characters make it unlikely to be found in any
+// other field.
+const streamPlaceholder = `"\u0000output.stream\u0000"`
+
+// dataStream is content read from a reader to be used as Data.
+type dataStream struct {
+	r           io.Reader
+	contentType string
+}
+
+// withStream sets the reader that Data is streamed from.
+func withStream(rd io.Reader, contentType string) SendOption {
+	return func(o *sendOptions) {
+		o.stream = &dataStream{
+			r:           rd,
+			contentType: contentType,
+		}
+	}
+}
+
+// DataFoundReader is used to send back data read from rd, for example when proxying
+// a large document, without reading it all into memory. contentHint is the content
+// type of the data and determines how it is placed in Data:
+//   - JSON content is used as is, so it must be valid JSON.
+//   - Text content, text/*, is sent as a JSON string.
+//   - Other content is sent as a base64 encoded string, like a []byte.
+//
+// Data is only streamed when using the JSON Encoder and the Responder is not
+// configured with options that need the entire response, such as WithSigningKey,
+// WithMaxResponseSize, WithEncryptedData, or pretty-printing, see WithPrettyJSON.
+// Otherwise rd is read in full and the content is handled like any other Data.
+//
+// Use WithRaw to send the content as the response body, without the Payload, with
+// contentHint as the Content-Type. Raw content is always streamed as is.
+func DataFoundReader(rd io.Reader, contentHint string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.DataFoundReader(rd, contentHint, w, opts...)
+	return
+}
+
+// DataFoundReader sends data read from rd. See the package-level DataFoundReader.
+func (r *Responder) DataFoundReader(rd io.Reader, contentHint string, w http.ResponseWriter, opts ...SendOption) (err error) {
+	opts = append(opts, withStream(rd, contentHint))
+	err = r.buildAndSend(true, TypeDataFound, nil, ErrorPayload{}, w, r.successCode, opts...)
+	return
+}
+
+// prepareStream sets p's Data for content that is streamed from a reader. True is
+// returned if the content can be streamed, in which case Data is set to a
+// placeholder that is replaced by writeStream. Otherwise, the content is read in
+// full and set as Data.
+func (r *Responder) prepareStream(p *Payload, w http.ResponseWriter, s *dataStream) (streaming bool, err error) {
+	streaming = r.encoder(w) == JSON &&
+		!r.jsonAPI &&
+		!r.hal &&
+		r.jwsSigner == nil &&
+		r.jweKeyFunc == nil &&
+		len(r.signingKey) == 0 &&
+		r.maxResponseSize <= 0 &&
+		!r.pretty(w)
+
+	if streaming {
+		p.Data = json.RawMessage(streamPlaceholder)
+		return
+	}
+
+	b, err := io.ReadAll(s.r)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case isJSON(s.contentType):
+		if !json.Valid(b) {
+			err = ErrInvalidJSON
+			return
+		}
+		p.Data = json.RawMessage(b)
+	case isText(s.contentType):
+		p.Data = string(b)
+	default:
+		p.Data = b
+	}
+
+	return
+}
+
+// writeStream writes the encoded Payload body to w with the placeholder Data
+// replaced by the content read from s. If truncate is true, see FaultTruncate, the
+// body ends where the Data would start since the length of the Data is not known.
+func (r *Responder) writeStream(w http.ResponseWriter, body []byte, s *dataStream, truncate bool) (err error) {
+	i := bytes.Index(body, []byte(streamPlaceholder))
+	if truncate {
+		if i < 0 {
+			i = len(body) / 2
+		}
+
+		_, err = w.Write(body[:i])
+		return
+	}
+	if i < 0 {
+		//The placeholder was removed, for example by a BeforeSendFunc replacing
+		//Data, so there is nothing to stream.
+		_, err = w.Write(body)
+		return
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.Write(body[:i])
+
+	switch {
+	case isJSON(s.contentType):
+		_, err = io.Copy(bw, s.r)
+	case isText(s.contentType):
+		err = r.copyJSONString(bw, s.r)
+	default:
+		bw.WriteByte('"')
+		b64 := base64.NewEncoder(base64.StdEncoding, bw)
+		_, err = io.Copy(b64, s.r)
+		b64.Close()
+		bw.WriteByte('"')
+	}
+	if err != nil {
+		r.log(w, "output.writeStream: could not stream data", "error", err)
+		return
+	}
+
+	bw.Write(body[i+len(streamPlaceholder):])
+	err = bw.Flush()
+	return
+}
+
+// streamRaw writes the content read from s as the response body.
+func (r *Responder) streamRaw(w http.ResponseWriter, s *dataStream, o sendOptions, responseCode int) (err error) {
+	contentType := s.contentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	o.setHeaders(w)
+
+	//Only the first half of the content is sent when truncating, see
+	//FaultTruncate. Nothing is sent after the headers if the length of the content
+	//is not known.
+	if o.truncateBody {
+		n, seekable := streamLength(s.r)
+		if seekable {
+			w.Header().Set("Content-Length", strconv.FormatInt(n, 10))
+		}
+
+		w.WriteHeader(responseCode)
+		if seekable && !isHead(w) {
+			_, err = io.CopyN(w, s.r, n/2)
+		}
+		return
+	}
+
+	if serveRange(w, s, responseCode) {
+		return
+	}
+
+	w.WriteHeader(responseCode)
+	if isHead(w) {
+		return
+	}
+
+	_, err = io.Copy(w, s.r)
+	return
+}
+
+// streamLength returns the number of bytes left to read from rd, if rd is an
+// io.Seeker.
+func streamLength(rd io.Reader) (n int64, ok bool) {
+	sk, ok := rd.(io.Seeker)
+	if !ok {
+		return
+	}
+
+	cur, err := sk.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, false
+	}
+	end, err := sk.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, false
+	}
+	if _, err = sk.Seek(cur, io.SeekStart); err != nil {
+		return 0, false
+	}
+
+	return end - cur, true
+}
+
+// copyJSONString writes the content read from rd to w as a JSON string, escaping as
+// json.Marshal does. Invalid UTF-8 is replaced with the Unicode replacement
+// character.
+func (r *Responder) copyJSONString(w *bufio.Writer, rd io.Reader) (err error) {
+	const hex = "0123456789abcdef"
+
+	br := bufio.NewReader(rd)
+	w.WriteByte('"')
+
+	for {
+		c, size, readErr := br.ReadRune()
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+
+		switch {
+		case c == utf8.RuneError && size == 1:
+			w.WriteString(jsonReplacement)
+		case c == '"' || c == '\\':
+			w.WriteByte('\\')
+			w.WriteByte(byte(c))
+		case c == '\n':
+			w.WriteString(`\n`)
+		case c == '\r':
+			w.WriteString(`\r`)
+		case c == '\t':
+			w.WriteString(`\t`)
+		case c < 0x20, r.escapeHTML && (c == '<' || c == '>' || c == '&'):
+			w.WriteString(`\u00`)
+			w.WriteByte(hex[c>>4])
+			w.WriteByte(hex[c&0xF])
+		case c == '\u2028' || c == '\u2029':
+			w.WriteString(`\u202`)
+			w.WriteByte(hex[c&0xF])
+		default:
+			w.WriteRune(c)
+		}
+	}
+
+	w.WriteByte('"')
+	return
+}
+
+// isText reports if contentType is a text type, such as text/plain.
+func isText(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(mt, "text/")
+}
@@ -0,0 +1,100 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCopyJSONString(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		escapeHTML bool
+	}{
+		{"plain", "hello", true},
+		{"quotes and backslashes", `a "b" \c`, true},
+		{"control characters", "a\nb\rc\td\x00e\x1f", true},
+		{"html", "<a>&</a>", true},
+		{"html unescaped", "<a>&</a>", false},
+		{"invalid utf-8", "a\xffb\xc3", true},
+		{"line and paragraph separators", "a\u2028b\u2029c", true},
+		{"multibyte", "héllo, 世界 🌍", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(WithEscapeHTML(tt.escapeHTML))
+
+			var got bytes.Buffer
+			bw := bufio.NewWriter(&got)
+			if err := r.copyJSONString(bw, strings.NewReader(tt.in)); err != nil {
+				t.Fatal(err)
+			}
+			bw.Flush()
+
+			var want bytes.Buffer
+			enc := json.NewEncoder(&want)
+			enc.SetEscapeHTML(tt.escapeHTML)
+			enc.Encode(tt.in)
+
+			if got.String() != strings.TrimSuffix(want.String(), "\n") {
+				t.Fatalf("got  %s\nwant %s", got.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestDataFoundReaderPretty(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		target      string
+		contentHint string
+		data        string
+		wantIndent  bool
+	}{
+		{"json", nil, "/", "application/json", `{"a":1}`, false},
+		{"pretty json", []Option{WithPrettyJSON(true)}, "/", "application/json", `{"a":1}`, true},
+		{"pretty param", []Option{WithPrettyParam("pretty")}, "/?pretty=1", "application/json", `{"a":1}`, true},
+		{"pretty param unset", []Option{WithPrettyParam("pretty")}, "/", "application/json", `{"a":1}`, false},
+		{"pretty text", []Option{WithPrettyJSON(true)}, "/", "text/plain", "a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.opts...)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if err := r.DataFoundReader(strings.NewReader(tt.data), tt.contentHint, Negotiate(w, req)); err != nil {
+				t.Fatal(err)
+			}
+
+			var p struct {
+				Data json.RawMessage
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+				t.Fatal(err)
+			}
+
+			//Data is indented along with the rest of the Payload.
+			want := []byte(tt.data)
+			if isJSON(tt.contentHint) && tt.wantIndent {
+				var b bytes.Buffer
+				json.Indent(&b, want, prettyIndent, prettyIndent)
+				want = b.Bytes()
+			}
+			if isText(tt.contentHint) {
+				want, _ = json.Marshal(tt.data)
+			}
+			if !bytes.Equal(p.Data, want) {
+				t.Fatalf("got Data %s, want %s", p.Data, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// errTimeout is the error sent by ErrorTimeout.
+var errTimeout = errors.New("timeout")
+
+// defaultTimeoutMsg is the Message sent by ErrorTimeout.
+const defaultTimeoutMsg = "The request took too long to handle, please try again."
+
+// ErrorTimeout is used when handling a request took too long, for example a
+// database query was cancelled due to the request's deadline. This returns an HTTP
+// status 504 with the TypeTimeout message type.
+//
+// This is also sent automatically, in place of any other response, when the
+// ResponseWriter carries the request (see Negotiate) and the request's context
+// deadline has already passed.
+func ErrorTimeout(w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorTimeout(w, opts...)
+	return
+}
+
+// ErrorTimeout sends a 504 error. See the package-level ErrorTimeout.
+func (r *Responder) ErrorTimeout(w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = r.sendError(TypeTimeout, errTimeout, defaultTimeoutMsg, nil, w, http.StatusGatewayTimeout, opts...)
+	return
+}
+
+// timedOut replaces the Payload, response code, and options being sent with the
+// timeout response if w carries a request whose context deadline has passed. True
+// is returned if the deadline has passed.
+func (r *Responder) timedOut(w http.ResponseWriter, p *Payload, responseCode *int, opts *[]SendOption) bool {
+	req := requestFrom(w)
+	if req == nil || !errors.Is(req.Context().Err(), context.DeadlineExceeded) {
+		return false
+	}
+
+	*p = r.newPayload(false, TypeTimeout, nil, r.newErrorPayload(errTimeout, defaultTimeoutMsg, w))
+	*responseCode = http.StatusGatewayTimeout
+
+	//Per-response options are ignored since the response is no longer what the
+	//caller intended.
+	*opts = nil
+
+	return true
+}
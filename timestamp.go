@@ -0,0 +1,39 @@
+package output
+
+import (
+	"strconv"
+	"time"
+)
+
+// Formats that can be used with WithTimestampFormat. Any other layout, as used by
+// time.Format, can be used as well.
+const (
+	//TimestampRFC3339 is RFC 3339 with second precision.
+	TimestampRFC3339 = time.RFC3339
+
+	//TimestampRFC3339Milli is RFC 3339 with millisecond precision. This is the
+	//default.
+	TimestampRFC3339Milli = "2006-01-02T15:04:05.000Z07:00"
+
+	//TimestampRFC3339Nano is RFC 3339 with nanosecond precision.
+	TimestampRFC3339Nano = time.RFC3339Nano
+
+	//TimestampUnix is the number of seconds since the Unix epoch.
+	TimestampUnix = "unix"
+
+	//TimestampUnixMilli is the number of milliseconds since the Unix epoch.
+	TimestampUnixMilli = "unixmilli"
+)
+
+// formatTimestamp formats t per format, handling the Unix formats that cannot be
+// expressed as a layout.
+func formatTimestamp(t time.Time, format string) string {
+	switch format {
+	case TimestampUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimestampUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	default:
+		return t.UTC().Format(format)
+	}
+}
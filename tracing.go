@@ -0,0 +1,57 @@
+package output
+
+import (
+	"context"
+	"net/http"
+)
+
+// Headers used to send the trace and span IDs of a response, see WithTracer.
+const (
+	HeaderTraceID = "X-Trace-ID"
+	HeaderSpanID  = "X-Span-ID"
+)
+
+// Tracer connects responses to distributed traces so that a response can be found
+// in, and correlated with, a trace. This package does not depend on a tracing
+// library, instead implement Tracer for the library you use. See the outputotel
+// package for an OpenTelemetry Tracer.
+type Tracer interface {
+	//TraceContext returns the IDs of the trace and span stored in ctx. Blank
+	//strings should be returned if ctx does not have a span.
+	TraceContext(ctx context.Context) (traceID, spanID string)
+
+	//RecordError is called for each error response sent so that the error can be
+	//recorded, for example as a span event, in the span stored in ctx.
+	RecordError(ctx context.Context, p *Payload, responseCode int)
+}
+
+// WithTracer sets the Tracer used to embed the current trace and span IDs in each
+// Payload and the response's X-Trace-ID and X-Span-ID headers, and to record each
+// error response in the current span. The trace is read from the request's context
+// so the ResponseWriter must carry the request (see Negotiate), or SendCtx or
+// ErrorCtx must be used.
+func WithTracer(t Tracer) Option {
+	return func(r *Responder) {
+		r.tracer = t
+	}
+}
+
+// trace sets the trace and span IDs on the Payload and response headers, and
+// records error responses, using the Responder's Tracer.
+func (r *Responder) trace(ctx context.Context, p *Payload, w http.ResponseWriter, responseCode int) {
+	if r.tracer == nil || ctx == nil {
+		return
+	}
+
+	traceID, spanID := r.tracer.TraceContext(ctx)
+	if traceID != "" {
+		p.TraceID, p.SpanID = traceID, spanID
+
+		w.Header().Set(HeaderTraceID, traceID)
+		w.Header().Set(HeaderSpanID, spanID)
+	}
+
+	if !p.OK {
+		r.tracer.RecordError(ctx, p, responseCode)
+	}
+}
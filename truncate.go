@@ -0,0 +1,81 @@
+package output
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Keys in a Payload's Meta set when Data is truncated, see WithTruncation.
+const (
+	//MetaTruncated is set to true when Data was truncated.
+	MetaTruncated = "Truncated"
+
+	//MetaNextCursor is set to the cursor used to request the items that were
+	//removed from Data.
+	MetaNextCursor = "NextCursor"
+)
+
+// CursorFunc returns the cursor used to request the items after last, the last item
+// included in a truncated Data. For example, this could return the ID of last for
+// keyset pagination.
+type CursorFunc func(last interface{}) string
+
+// WithTruncation causes list Data, slices and arrays, with more than n items to be
+// truncated to n items instead of sending a huge response. The Payload's Meta will
+// have MetaTruncated set to true and MetaNextCursor set to the cursor returned by
+// cursor so that the client can request the remaining items. If cursor is nil, the
+// cursor is the number of items sent, for use as an offset.
+//
+// This is an alternative to WithMaxResponseSize that lets oversized queries degrade
+// gracefully. Only successful responses are truncated.
+func WithTruncation(n int, cursor CursorFunc) Option {
+	return func(r *Responder) {
+		r.truncateItems = n
+		r.truncateCursor = cursor
+	}
+}
+
+// truncate truncates p's Data, if it is a list with too many items, and notes so in
+// the Payload's Meta.
+func (r *Responder) truncate(p *Payload) {
+	if r.truncateItems <= 0 || !p.OK || p.Data == nil {
+		return
+	}
+
+	v := reflect.ValueOf(p.Data)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return
+	}
+
+	//Byte slices are encoded as a single value, not a list.
+	if v.Type().Elem().Kind() == reflect.Uint8 {
+		return
+	}
+	if v.Len() <= r.truncateItems {
+		return
+	}
+
+	//Arrays cannot be resliced unless addressable, so a slice of the same element
+	//type is always sent.
+	t := reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), r.truncateItems, r.truncateItems)
+	reflect.Copy(t, v)
+	p.Data = t.Interface()
+
+	cursor := strconv.Itoa(r.truncateItems)
+	if r.truncateCursor != nil {
+		cursor = r.truncateCursor(t.Index(r.truncateItems - 1).Interface())
+	}
+
+	//A new map is used so that a map provided by the caller is not modified.
+	meta := make(map[string]interface{}, len(p.Meta)+2)
+	for k, v := range p.Meta {
+		meta[k] = v
+	}
+	meta[MetaTruncated] = true
+	meta[MetaNextCursor] = cursor
+
+	p.Meta = meta
+}
@@ -0,0 +1,68 @@
+package output
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// TypedPayload is a Payload whose Data is a T, rather than an interface{}, so that
+// the shape of Data is checked at compile time and tools that derive schemas using
+// reflection, such as OpenAPI generators, can see the type of Data.
+//
+// TypedPayload is encoded exactly like a Payload. When decoding, Data is decoded
+// directly into a T. Use Untyped to send a TypedPayload with a Responder's Send.
+type TypedPayload[T any] struct {
+	Payload
+
+	//Data is arbitrary data to send back to the client, see Payload.
+	Data T `json:",omitempty"`
+}
+
+// Untyped returns the TypedPayload as a Payload with Data set to the TypedPayload's
+// Data.
+func (p TypedPayload[T]) Untyped() Payload {
+	u := p.Payload
+	u.Data = p.Data
+	return u
+}
+
+// MarshalJSON encodes the TypedPayload as a Payload.
+func (p TypedPayload[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Untyped())
+}
+
+// SendT sends a TypedPayload. See Send.
+func SendT[T any](p TypedPayload[T], w http.ResponseWriter, responseCode int, opts ...SendOption) (err error) {
+	err = std.Send(p.Untyped(), w, responseCode, opts...)
+	return
+}
+
+// SuccessT is the same as Success except that the type of data is checked at
+// compile time.
+func SuccessT[T any](msgType MessageType, data T, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.Success(msgType, data, w, opts...)
+	return
+}
+
+// DataFoundT is the same as DataFound except that the type of data is checked at
+// compile time.
+//
+//	output.DataFoundT[[]User](users, w)
+func DataFoundT[T any](data T, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.DataFound(data, w, opts...)
+	return
+}
+
+// InsertOKT is the same as InsertOKWithData except that the type of data is checked
+// at compile time.
+func InsertOKT[T any](data T, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.InsertOKWithData(data, w, opts...)
+	return
+}
+
+// UpdateOKT is the same as UpdateOKWithData except that the type of data is
+// checked at compile time.
+func UpdateOKT[T any](data T, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.UpdateOKWithData(data, w, opts...)
+	return
+}
@@ -0,0 +1,227 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TypeScript returns TypeScript definitions for the Payloads sent by the default
+// Responder. See Responder.TypeScript.
+func TypeScript() []byte {
+	return std.TypeScript()
+}
+
+// TypeScript returns TypeScript definitions for the Payload, the ErrorPayload and
+// other types defined in this package, the Data types registered with
+// RegisterDataType, and the registered message types, so that front-end code does
+// not need to maintain these definitions by hand. Keys match the Responder's
+// configuration, as with Schema.
+//
+// The Payload is defined as a generic interface, Payload<T>, where T is the type of
+// Data. A type is also defined for each message type with a registered Data type,
+// named after the message type, for example UserFoundPayload for "userFound".
+//
+// The definitions are typically written to a file using go generate and a small
+// program that configures the Responder the same as your app:
+//
+//	//go:generate go run ./cmd/gentypes
+//
+//	func main() {
+//		r := api.NewResponder()
+//		os.WriteFile("web/src/api/output.ts", r.TypeScript(), 0644)
+//	}
+func (r *Responder) TypeScript() []byte {
+	sb := newSchemaBuilder(r.keys, "")
+	sb.defTypes["Payload"] = reflect.TypeOf(Payload{})
+	sb.defs["Payload"] = r.envelopeSchema(sb)
+
+	r.mu.RLock()
+	msgTypes := make([]string, 0, len(r.messageTypes))
+	for t := range r.messageTypes {
+		msgTypes = append(msgTypes, string(t))
+	}
+	dataTypes := make(map[string]string, len(r.dataTypes))
+	for t, dt := range r.dataTypes {
+		if dt != nil {
+			dataTypes[string(t)] = tsType(sb.typeSchema(dt, false))
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Strings(msgTypes)
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by github.com/c9845/output. DO NOT EDIT.\n")
+
+	//Message types.
+	b.WriteString("\nexport type MessageType =")
+	for _, t := range msgTypes {
+		b.WriteString("\n  | " + tsLiteral(t))
+	}
+	b.WriteString(";\n")
+
+	//The Payload, the types it uses, and the registered Data types.
+	names := make([]string, 0, len(sb.defs))
+	for name := range sb.defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dataKey := sb.keys.key("Data", "Data")
+	for _, name := range names {
+		s := sb.defs[name].(map[string]interface{})
+
+		if name == "Payload" {
+			props := s["properties"].(map[string]interface{})
+			props[dataKey] = map[string]interface{}{"tsType": "T"}
+
+			b.WriteString("\nexport interface Payload<T = unknown> ")
+			b.WriteString(tsObject(s, ""))
+			b.WriteString("\n")
+			continue
+		}
+
+		if _, ok := s["properties"]; ok {
+			b.WriteString("\nexport interface " + name + " " + tsObject(s, "") + "\n")
+		} else {
+			b.WriteString("\nexport type " + name + " = " + tsType(s) + ";\n")
+		}
+	}
+
+	//A Payload for each message type with a registered Data type.
+	typeKey := tsKey(sb.keys.key("Type", "Type"))
+	for _, t := range msgTypes {
+		dt, ok := dataTypes[t]
+		if !ok {
+			continue
+		}
+
+		b.WriteString("\nexport type " + tsIdentifier(t) + "Payload = Payload<" + dt + "> & { " + typeKey + ": " + tsLiteral(t) + " };\n")
+	}
+
+	return b.Bytes()
+}
+
+// tsType returns the TypeScript type for a JSON Schema built by schemaBuilder.
+func tsType(s map[string]interface{}) string {
+	if t, ok := s["tsType"].(string); ok {
+		return t
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		return ref
+	}
+	if c, ok := s["const"]; ok {
+		if str, ok := c.(string); ok {
+			return tsLiteral(str)
+		}
+	}
+	if all, ok := s["allOf"].([]interface{}); ok {
+		parts := make([]string, len(all))
+		for i, sub := range all {
+			parts[i] = tsType(sub.(map[string]interface{}))
+		}
+		return strings.Join(parts, " & ")
+	}
+
+	switch s["type"] {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return tsType(s["items"].(map[string]interface{})) + "[]"
+	case "object":
+		if _, ok := s["properties"]; ok {
+			return tsObject(s, "")
+		}
+		if ap, ok := s["additionalProperties"].(map[string]interface{}); ok {
+			return "Record<string, " + tsType(ap) + ">"
+		}
+		return "Record<string, unknown>"
+	default:
+		return "unknown"
+	}
+}
+
+// tsObject returns the TypeScript object type for a JSON Schema of an object with
+// properties. indent is the indentation of the line the object starts on.
+func tsObject(s map[string]interface{}, indent string) string {
+	props, _ := s["properties"].(map[string]interface{})
+	required, _ := s["required"].([]string)
+
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, k := range keys {
+		optional := "?"
+		for _, r := range required {
+			if r == k {
+				optional = ""
+				break
+			}
+		}
+
+		ps := props[k].(map[string]interface{})
+		t := tsType(ps)
+		if _, ok := ps["properties"]; ok {
+			t = tsObject(ps, indent+"  ")
+		}
+
+		b.WriteString(indent + "  " + tsKey(k) + optional + ": " + t + ";\n")
+	}
+	b.WriteString(indent + "}")
+
+	return b.String()
+}
+
+// tsIdentifierRegexp matches valid TypeScript identifiers that do not need to be
+// quoted when used as a key.
+var tsIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsKey returns k as a TypeScript property key, quoting it if needed.
+func tsKey(k string) string {
+	if tsIdentifierRegexp.MatchString(k) {
+		return k
+	}
+
+	return tsLiteral(k)
+}
+
+// tsLiteral returns s as a TypeScript string literal.
+func tsLiteral(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// tsIdentifier returns a message type as a PascalCase TypeScript identifier, for
+// example "userFound" is returned as "UserFound".
+func tsIdentifier(msgType string) string {
+	var b strings.Builder
+	upper := true
+	for _, c := range msgType {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			c = unicode.ToUpper(c)
+			upper = false
+		}
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
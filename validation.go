@@ -0,0 +1,46 @@
+package output
+
+import (
+	"errors"
+	"net/http"
+)
+
+// errValidation is the error sent by ErrorValidation.
+var errValidation = errors.New("validation error")
+
+// defaultValidationMsg is the Message sent by ErrorValidation.
+const defaultValidationMsg = "One or more fields are invalid."
+
+// FieldError describes why a single field failed validation.
+type FieldError struct {
+	//Field is the name of the field that failed validation. This should match the
+	//name used by the client, for example the name of a form input.
+	Field string
+
+	//Rule is the validation rule that failed, for example "required" or "max".
+	//This allows clients to handle specific failures programmatically.
+	Rule string `json:",omitempty"`
+
+	//Message is a human-friendly explanation of how to fix the field.
+	Message string `json:",omitempty"`
+}
+
+// ErrorValidation is used when input validation fails for one or more specific
+// fields. Each field is listed in ErrorData's Fields so that a GUI can highlight
+// each invalid field. This returns an HTTP status 422.
+func ErrorValidation(fields []FieldError, w http.ResponseWriter, opts ...SendOption) (err error) {
+	err = std.ErrorValidation(fields, w, opts...)
+	return
+}
+
+// ErrorValidation sends a 422 error listing invalid fields. See the package-level
+// ErrorValidation.
+func (r *Responder) ErrorValidation(fields []FieldError, w http.ResponseWriter, opts ...SendOption) (err error) {
+	ep := r.newErrorPayload(errValidation, defaultValidationMsg, w)
+	ep.Fields = fields
+
+	r.log(w, "output.ErrorValidation", "fields", fields)
+
+	err = r.buildAndSend(false, TypeError, nil, ep, w, http.StatusUnprocessableEntity, opts...)
+	return
+}
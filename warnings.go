@@ -0,0 +1,24 @@
+package output
+
+// Warning is a non-fatal issue with a request, such as an ignored parameter, a soft
+// limit that was reached, or data that is only partially returned. Warnings allow a
+// request to succeed while still telling the client something is not quite right.
+type Warning struct {
+	//Code is a stable, machine-readable, identifier for the warning.
+	Code string `json:",omitempty"`
+
+	//Message is a human-friendly description of the warning.
+	Message string
+}
+
+// WithWarning adds a Warning to the Payload's Warnings.
+//
+//	output.DataFound(users, w, output.WithWarning("limitCapped", "Limit capped to 100 results."))
+func WithWarning(code, message string) SendOption {
+	return func(o *sendOptions) {
+		o.warnings = append(o.warnings, Warning{
+			Code:    code,
+			Message: message,
+		})
+	}
+}
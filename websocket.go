@@ -0,0 +1,84 @@
+package output
+
+import (
+	"context"
+	"sync"
+)
+
+// WebSocketConn is a websocket connection that Payloads are written to. This package
+// does not depend on a websocket library, see the outputwebsocket package for
+// adapters for gorilla/websocket and nhooyr.io/websocket.
+type WebSocketConn interface {
+	//WriteText writes b as a single text message.
+	WriteText(ctx context.Context, b []byte) error
+}
+
+// WebSocket sends Payloads as websocket messages, one JSON encoded Payload per text
+// message, so that realtime endpoints use the same Payload format that clients
+// already parse.
+//
+// A WebSocket is safe for concurrent use, messages are written one at a time.
+type WebSocket struct {
+	r    *Responder
+	conn WebSocketConn
+	mu   sync.Mutex
+}
+
+// NewWebSocket returns a WebSocket that sends Payloads over conn using the default
+// Responder. See Responder.NewWebSocket.
+func NewWebSocket(conn WebSocketConn) *WebSocket {
+	return std.NewWebSocket(conn)
+}
+
+// NewWebSocket returns a WebSocket that sends Payloads over conn. The websocket
+// handshake must already be complete.
+//
+//	c, err := upgrader.Upgrade(w, req, nil)
+//	if err != nil {
+//		return
+//	}
+//	ws := r.NewWebSocket(outputwebsocket.Gorilla(c))
+//	ws.Success(ctx, "priceChanged", price)
+func (r *Responder) NewWebSocket(conn WebSocketConn) *WebSocket {
+	return &WebSocket{
+		r:    r,
+		conn: conn,
+	}
+}
+
+// Send sends a Payload as a message. The Payload is prepared the same as a response
+// sent with Send, see Responder.Publish.
+func (s *WebSocket) Send(ctx context.Context, p Payload) (err error) {
+	b, err := s.r.encodeMessage(ctx, &p)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = s.conn.WriteText(ctx, b)
+	return
+}
+
+// Success sends a successful Payload as a message.
+func (s *WebSocket) Success(ctx context.Context, msgType MessageType, data interface{}) (err error) {
+	err = s.Send(ctx, Payload{
+		OK:   true,
+		Type: string(msgType),
+		Data: data,
+	})
+	return
+}
+
+// Error sends an error Payload as a message.
+func (s *WebSocket) Error(ctx context.Context, errType error, errMsg string) (err error) {
+	s.r.log(nil, "output.WebSocket.Error", "error", errType, "message", errMsg)
+
+	err = s.Send(ctx, Payload{
+		OK:        false,
+		Type:      string(TypeError),
+		ErrorData: s.r.newErrorPayload(errType, errMsg, nil),
+	})
+	return
+}
@@ -0,0 +1,30 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Bytes returns the Payload encoded as JSON, the same as it is sent in a response.
+// This allows the Payload to be used outside of HTTP, such as for messages sent to
+// a queue.
+func (p Payload) Bytes() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// WriteTo writes the Payload, encoded as JSON and followed by a newline, to w. This
+// allows the Payload to be written to files, stdout, or test buffers, for example as
+// JSON lines. This implements io.WriterTo.
+//
+//	p := output.Payload{OK: true, Type: "jobFinished", Data: job}
+//	p.WriteTo(os.Stdout)
+func (p Payload) WriteTo(w io.Writer) (n int64, err error) {
+	b, err := p.Bytes()
+	if err != nil {
+		return
+	}
+
+	written, err := w.Write(append(b, '\n'))
+	n = int64(written)
+	return
+}
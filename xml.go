@@ -0,0 +1,237 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// XML is an Encoder that encodes responses as XML. Use it with WithEncoders, either
+// alone or alongside JSON when negotiating the encoding based on the Accept header.
+var XML Encoder = xmlEncoder{}
+
+// xmlEncoder encodes responses as XML.
+type xmlEncoder struct{}
+
+// ContentType implements Encoder.
+func (xmlEncoder) ContentType() string {
+	return "application/xml; charset=UTF-8"
+}
+
+// Encode implements Encoder.
+func (xmlEncoder) Encode(w io.Writer, v interface{}) (err error) {
+	x, err := xml.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	_, err = io.WriteString(w, xml.Header)
+	if err != nil {
+		return
+	}
+
+	_, err = w.Write(x)
+	return
+}
+
+// MarshalXML implements xml.Marshaler. The Payload is encoded as a Payload element
+// with a child element per field, named the same as the field. Data is encoded as
+// follows:
+//   - Structs, and types implementing xml.Marshaler, are encoded per the rules of
+//     encoding/xml within the Data element. This honors xml struct tags.
+//   - Maps are encoded with a child element per key, sorted by key. If a key is not
+//     a valid XML element name, an Entry element with a key attribute is used.
+//   - Slices and arrays are encoded with an Item child element per value.
+//   - Other values are encoded as the character data of the Data element.
+func (p Payload) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "Payload"}
+	return encodeXMLStruct(e, start, reflect.ValueOf(payloadXML(p)), p.keys.key)
+}
+
+// payloadXML is used to encode a Payload without recursing into MarshalXML.
+type payloadXML Payload
+
+// pkgPath is the import path of this package, used to identify types defined here.
+var pkgPath = reflect.TypeOf(Payload{}).PkgPath()
+
+// xmlMarshalerType is used to check if a type implements xml.Marshaler.
+var xmlMarshalerType = reflect.TypeOf((*xml.Marshaler)(nil)).Elem()
+
+// encodeXML encodes v as an element using start.
+func encodeXML(e *xml.Encoder, start xml.StartElement, v reflect.Value) error {
+	//Dereference, skipping nil values since there is nothing to encode.
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+
+	//Types that know how to encode themselves.
+	if v.Type().Implements(xmlMarshalerType) || reflect.PointerTo(v.Type()).Implements(xmlMarshalerType) {
+		return e.EncodeElement(v.Interface(), start)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		//Structs defined in this package are encoded field by field so that
+		//arbitrary data stored in interface fields can be encoded. Other structs,
+		//i.e. user-defined data, are encoded per encoding/xml.
+		if v.Type().PkgPath() != pkgPath || v.Type() == reflect.TypeOf(time.Time{}) {
+			return e.EncodeElement(v.Interface(), start)
+		}
+
+		return encodeXMLStruct(e, start, v, nil)
+
+	case reflect.Map:
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		for _, k := range keys {
+			key := fmt.Sprint(k.Interface())
+
+			child := xml.StartElement{Name: xml.Name{Local: key}}
+			if !validXMLName(key) {
+				child = xml.StartElement{
+					Name: xml.Name{Local: "Entry"},
+					Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+				}
+			}
+
+			if err := encodeXML(e, child, v.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+
+		return e.EncodeToken(start.End())
+
+	case reflect.Slice, reflect.Array:
+		//Byte slices are handled by encoding/xml as character data.
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return e.EncodeElement(v.Interface(), start)
+		}
+
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeXML(e, xml.StartElement{Name: xml.Name{Local: "Item"}}, v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		return e.EncodeToken(start.End())
+
+	default:
+		return e.EncodeElement(v.Interface(), start)
+	}
+}
+
+// encodeXMLStruct encodes the struct v as an element using start, with a child
+// element per field named per the field's json struct tag. If key is provided, it
+// is used to alter the name of each child element.
+func encodeXMLStruct(e *xml.Encoder, start xml.StartElement, v reflect.Value, key func(field, name string) string) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, skip := jsonFieldName(f)
+		if skip {
+			continue
+		}
+
+		if key != nil {
+			if n := key(f.Name, name); validXMLName(n) {
+				name = n
+			}
+		}
+
+		fv := v.Field(i)
+		if omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		if err := encodeXML(e, xml.StartElement{Name: xml.Name{Local: name}}, fv); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// jsonFieldName returns the name of a struct field per its json struct tag, if the
+// field should be omitted when empty, and if the field should be skipped entirely.
+func jsonFieldName(f reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		skip = true
+		return
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+
+	for _, o := range strings.Split(opts, ",") {
+		if o == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return
+}
+
+// isEmptyValue reports if v is empty per encoding/json's omitempty rule so that the
+// same fields are omitted as when encoding JSON. For example, an empty but non-nil
+// Meta is omitted. Structs, such as ErrorData, are empty when they are zero.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	default:
+		//False, 0, and nil pointers and interfaces.
+		return v.IsZero()
+	}
+}
+
+// validXMLName reports if s can be used as an XML element name.
+func validXMLName(s string) bool {
+	if s == "" || strings.HasPrefix(strings.ToLower(s), "xml") {
+		return false
+	}
+
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestXMLOmitEmpty(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        Payload
+		want     []string
+		wantNone []string
+	}{
+		{
+			name:     "nil",
+			p:        Payload{Type: "a"},
+			wantNone: []string{"<Meta", "<Errors", "<Warnings", "<ErrorData", "<Data"},
+		},
+		{
+			name:     "empty meta",
+			p:        Payload{Type: "a", Meta: map[string]interface{}{}},
+			wantNone: []string{"<Meta"},
+		},
+		{
+			name:     "empty errors and warnings",
+			p:        Payload{Type: "a", Errors: []ErrorPayload{}, Warnings: []Warning{}},
+			wantNone: []string{"<Errors", "<Warnings"},
+		},
+		{
+			name:     "empty details",
+			p:        Payload{Type: "a", ErrorData: ErrorPayload{Error: "b", Details: map[string]interface{}{}}},
+			want:     []string{"<ErrorData><Error>b</Error></ErrorData>"},
+			wantNone: []string{"<Details"},
+		},
+		{
+			name: "meta",
+			p:    Payload{Type: "a", Meta: map[string]interface{}{"b": 1}},
+			want: []string{"<Meta><b>1</b></Meta>"},
+		},
+		{
+			name: "empty data is kept",
+			p:    Payload{Type: "a", Data: map[string]interface{}{}},
+			want: []string{"<Data></Data>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := xml.NewEncoder(&b).Encode(tt.p); err != nil {
+				t.Fatal(err)
+			}
+
+			got := b.String()
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Fatalf("got %s, want %s", got, s)
+				}
+			}
+			for _, s := range tt.wantNone {
+				if strings.Contains(got, s) {
+					t.Fatalf("got %s, want no %s", got, s)
+				}
+			}
+		})
+	}
+}
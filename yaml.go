@@ -0,0 +1,170 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// YAML is an Encoder that encodes responses as YAML, for endpoints whose responses
+// are mostly read by humans, such as internal ops or configuration endpoints. The
+// Payload, and the Data stored in it, are encoded with the same field names as with
+// JSON.
+var YAML Encoder = &genericEncoder{
+	contentType: "application/yaml; charset=UTF-8",
+	encode:      encodeYAML,
+}
+
+// yamlIndent is the number of spaces nested values are indented by.
+const yamlIndent = 2
+
+// encodeYAML writes the generic value g to w as a YAML document.
+func encodeYAML(w io.Writer, g interface{}) error {
+	bw := bufio.NewWriter(w)
+
+	switch v := g.(type) {
+	case genericMap:
+		if len(v) == 0 {
+			bw.WriteString("{}\n")
+			break
+		}
+		writeYAMLMap(bw, v, 0, false)
+
+	case []interface{}:
+		if len(v) == 0 {
+			bw.WriteString("[]\n")
+			break
+		}
+		writeYAMLList(bw, v, 0, false)
+
+	default:
+		bw.WriteString(yamlScalar(g) + "\n")
+	}
+
+	return bw.Flush()
+}
+
+// writeYAMLMap writes the members of m as a block mapping indented by indent
+// spaces. If inline is true, the first member is written without indentation since
+// it follows a list item's "- ".
+func writeYAMLMap(w *bufio.Writer, m genericMap, indent int, inline bool) {
+	for i, member := range m {
+		if i > 0 || !inline {
+			w.WriteString(strings.Repeat(" ", indent))
+		}
+
+		w.WriteString(yamlString(member.Key) + ":")
+		writeYAMLChild(w, member.Value, indent)
+	}
+}
+
+// writeYAMLList writes the elements of a as a block sequence indented by indent
+// spaces. If inline is true, the first element is written without indentation.
+func writeYAMLList(w *bufio.Writer, a []interface{}, indent int, inline bool) {
+	for i, e := range a {
+		if i > 0 || !inline {
+			w.WriteString(strings.Repeat(" ", indent))
+		}
+		w.WriteString("- ")
+
+		switch v := e.(type) {
+		case genericMap:
+			if len(v) > 0 {
+				writeYAMLMap(w, v, indent+yamlIndent, true)
+				continue
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				writeYAMLList(w, v, indent+yamlIndent, true)
+				continue
+			}
+		}
+
+		w.WriteString(yamlScalar(e) + "\n")
+	}
+}
+
+// writeYAMLChild writes the value of a mapping member, following the key's ":".
+// Non-empty maps and lists are written as indented blocks on the following lines.
+func writeYAMLChild(w *bufio.Writer, g interface{}, indent int) {
+	switch v := g.(type) {
+	case genericMap:
+		if len(v) > 0 {
+			w.WriteString("\n")
+			writeYAMLMap(w, v, indent+yamlIndent, false)
+			return
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			w.WriteString("\n")
+			writeYAMLList(w, v, indent+yamlIndent, false)
+			return
+		}
+	}
+
+	w.WriteString(" " + yamlScalar(g) + "\n")
+}
+
+// yamlScalar returns g, a generic value that is not a non-empty map or list, as a
+// YAML scalar.
+func yamlScalar(g interface{}) string {
+	switch v := g.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case json.Number:
+		return v.String()
+	case string:
+		return yamlString(v)
+	case genericMap:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	default:
+		return "null"
+	}
+}
+
+// yamlReserved are plain scalars that YAML parsers interpret as something other than
+// a string, so strings with these values must be quoted.
+var yamlReserved = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"y": true, "n": true, "null": true, "~": true,
+}
+
+// yamlString returns s as a YAML scalar. s is written plain if it is unambiguous,
+// otherwise it is double-quoted, using JSON escaping which YAML supports.
+func yamlString(s string) string {
+	if yamlPlain(s) {
+		return s
+	}
+
+	return string(appendJSONString(nil, s))
+}
+
+// yamlPlain reports if s can be written as a plain, unquoted, YAML scalar. This is
+// conservative: only strings starting with a letter and made of letters, digits,
+// spaces, and a few punctuation characters are written plain.
+func yamlPlain(s string) bool {
+	if s == "" || yamlReserved[strings.ToLower(s)] || strings.HasSuffix(s, " ") {
+		return false
+	}
+
+	for i, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case i == 0:
+			return false
+		case c >= '0' && c <= '9', c == ' ', c == '_', c == '-', c == '.', c == '/':
+		default:
+			return false
+		}
+	}
+
+	return true
+}